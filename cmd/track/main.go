@@ -9,7 +9,9 @@ import (
 
 	"github.com/jasonwoodland/track/pkg/cleanup"
 	"github.com/jasonwoodland/track/pkg/cmd"
+	"github.com/jasonwoodland/track/pkg/completion"
 	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/webhook"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/urfave/cli/v2"
 )
@@ -30,7 +32,9 @@ func main() {
 		<-c
 		fmt.Println()
 		fmt.Printf("\033[?1049l")
-		cleanup.Cleanup()
+		if cleanup.Cleanup != nil {
+			cleanup.Cleanup()
+		}
 		os.Exit(0)
 	}()
 
@@ -39,22 +43,48 @@ func main() {
 		Usage:                  "Track time for projects and tasks",
 		EnableBashCompletion:   true,
 		UseShortOptionHandling: true,
+		BashComplete:           completion.CommandCompletion,
+
+		// Send any webhook events queued by the command, along with any
+		// which are due to be retried. Read-only commands and completion
+		// don't queue events, so they don't wait on deliveries.
+		After: func(c *cli.Context) error {
+			if webhook.Queued() {
+				webhook.Deliver()
+			}
+			return nil
+		},
 
 		Commands: cli.Commands{
 			cmd.Start,
 			cmd.Status,
+			cmd.Ui,
 			cmd.Shift,
 			cmd.Stop,
 			cmd.Add,
 			cmd.Cancel,
 			cmd.Log,
 			cmd.Report,
+			cmd.Export,
+			cmd.Import,
 			cmd.Timeline,
+			cmd.Heatmap,
 			cmd.Projects,
 			cmd.ProjectCmds,
+			cmd.ClientCmds,
 			cmd.TaskCmds,
 			cmd.FrameCmds,
 			cmd.Daily,
+			cmd.Timesheet,
+			cmd.Balance,
+			cmd.AbsenceCmds,
+			cmd.Holidays,
+			cmd.ConfigCmds,
+			cmd.WebhookCmds,
+			cmd.GitCmds,
+			cmd.Serve,
+			cmd.Metrics,
+			cmd.Completion,
 		},
 	}
 
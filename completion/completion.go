@@ -0,0 +1,14 @@
+// Package completion contains the shell completion scripts printed by the
+// completion command
+package completion
+
+import _ "embed"
+
+//go:embed track.bash
+var Bash string
+
+//go:embed _track
+var Zsh string
+
+//go:embed track.fish
+var Fish string
@@ -0,0 +1,16 @@
+# bash completion for track
+
+_track() {
+  local cur opts
+  local IFS=$'\n'
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  if [[ "$cur" == "-"* ]]; then
+    opts=$(_TRACK_COMPLETION_SHELL=bash "${COMP_WORDS[@]:0:COMP_CWORD}" - --generate-bash-completion 2>/dev/null)
+  else
+    opts=$(_TRACK_COMPLETION_SHELL=bash "${COMP_WORDS[@]:0:COMP_CWORD}" --generate-bash-completion 2>/dev/null)
+  fi
+
+  COMPREPLY=($(compgen -W "$opts" -- "$cur"))
+}
+
+complete -o default -F _track track
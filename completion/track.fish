@@ -0,0 +1,13 @@
+# fish completion for track
+
+function __track_complete
+    set -l tokens (commandline -opc)
+    set -l cur (commandline -ct)
+    if string match -q -- '-*' $cur
+        env _TRACK_COMPLETION_SHELL=fish $tokens - --generate-bash-completion 2>/dev/null
+    else
+        env _TRACK_COMPLETION_SHELL=fish $tokens --generate-bash-completion 2>/dev/null
+    end
+end
+
+complete -c track -f -a '(__track_complete)'
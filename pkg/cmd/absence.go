@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/completion"
+	"github.com/jasonwoodland/track/pkg/ical"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/presenter"
+	"github.com/jasonwoodland/track/pkg/util"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+var AbsenceCmds = &cli.Command{
+	Name:         "absence",
+	Usage:        "Manage vacation, sick days and holidays",
+	BashComplete: completion.CommandCompletion,
+	Subcommands: []*cli.Command{
+		{
+			Name:      "add",
+			Usage:     "Add an absence for a date or range of dates",
+			ArgsUsage: "date|from..to",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "type",
+					Aliases: []string{"t"},
+					Usage:   "Type of absence (" + strings.Join(model.AbsenceTypes, ", ") + ")",
+					Value:   "vacation",
+				},
+				&cli.StringFlag{
+					Name:    "description",
+					Aliases: []string{"d"},
+					Usage:   "Description of the absence",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 1 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				absenceType := c.String("type")
+				if !isAbsenceType(absenceType) {
+					color.Printf(view.BadAbsenceType, absenceType, strings.Join(model.AbsenceTypes, ", "))
+					return nil
+				}
+
+				from, to := util.DateRangeFromShorthand(c.Args().Get(0))
+				a := model.AddAbsence(absenceType, from, to, c.String("description"))
+
+				numDays := a.GetNumDays()
+				s := "s"
+				if numDays == 1 {
+					s = ""
+				}
+
+				color.Printf(
+					view.AddedAbsence,
+					a.Type,
+					a.StartDate.Format("Mon Jan 02"),
+					a.EndDate.Format("Mon Jan 02 2006"),
+					numDays,
+					s,
+				)
+				return nil
+			},
+		},
+		{
+			Name:    "list",
+			Aliases: []string{"ls"},
+			Usage:   "List absences",
+			BashComplete: func(c *cli.Context) {
+				completion.ShowFlagCompletion(c)
+			},
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "from",
+					Aliases: []string{"f"},
+					Usage:   "Start date from which to include absences",
+				},
+				&cli.StringFlag{
+					Name:    "to",
+					Aliases: []string{"t"},
+					Usage:   "End date from which to include absences",
+				},
+				&cli.StringFlag{
+					Name:  "type",
+					Usage: "Only include absences of a type",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				printAbsences(c.String("from"), c.String("to"), c.String("type"))
+				return nil
+			},
+		},
+		{
+			Name:      "remove",
+			Aliases:   []string{"rm"},
+			Usage:     "Delete an absence",
+			ArgsUsage: "id",
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 1 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				id, _ := strconv.ParseInt(c.Args().Get(0), 10, 64)
+				a := model.GetAbsenceById(id)
+				if a == nil {
+					color.Printf(view.AbsenceDoesNotExist, c.Args().Get(0))
+					return nil
+				}
+
+				if !presenter.Confirm(color.Sprintf(
+					view.ConfirmDeleteAbsence,
+					a.Type,
+					a.StartDate.Format("Mon Jan 02"),
+					a.EndDate.Format("Mon Jan 02 2006"),
+				), false) {
+					return nil
+				}
+
+				a.Remove()
+				color.Println(view.Deleted)
+				return nil
+			},
+		},
+	},
+}
+
+var Holidays = &cli.Command{
+	Name:  "holidays",
+	Usage: "List public holidays",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "from",
+			Aliases: []string{"f"},
+			Usage:   "Start date from which to include holidays",
+		},
+		&cli.StringFlag{
+			Name:    "to",
+			Aliases: []string{"t"},
+			Usage:   "End date from which to include holidays",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		printAbsences(c.String("from"), c.String("to"), "holiday")
+		return nil
+	},
+	BashComplete: completion.CommandCompletion,
+	Subcommands: []*cli.Command{
+		{
+			Name:      "import",
+			Usage:     "Import public holidays from an iCalendar file",
+			ArgsUsage: "file.ics",
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 1 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				f, err := os.Open(c.Args().Get(0))
+				if err != nil {
+					log.Fatal(err)
+				}
+				defer f.Close()
+
+				events, err := ical.Parse(f)
+				if err != nil {
+					log.Fatal(err)
+				}
+
+				imported, skipped := 0, 0
+				for _, e := range events {
+					// DTEND is exclusive, so the last day of the holiday is
+					// the day before
+					from := e.Start
+					to := e.End.AddDate(0, 0, -1)
+					if !e.AllDay || to.Before(from) {
+						to = from
+					}
+
+					// Skip holidays which have already been imported
+					exists := false
+					for _, a := range model.GetAbsences(from, to) {
+						if a.Type == "holiday" && a.StartDate.Equal(from) && a.Description == e.Summary {
+							exists = true
+						}
+					}
+					if exists {
+						skipped++
+						continue
+					}
+
+					model.AddAbsence("holiday", from, to, e.Summary)
+					imported++
+				}
+
+				s := "s"
+				if imported == 1 {
+					s = ""
+				}
+				color.Printf(view.ImportedHolidaysSkipped, imported, s, skipped)
+				return nil
+			},
+		},
+	},
+}
+
+func isAbsenceType(absenceType string) bool {
+	for _, t := range model.AbsenceTypes {
+		if t == absenceType {
+			return true
+		}
+	}
+	return false
+}
+
+func printAbsences(fromFlag, toFlag, absenceType string) {
+	from := time.Time{}
+	to := time.Date(9999, 12, 31, 0, 0, 0, 0, time.Local)
+	if fromFlag != "" {
+		from = util.TimeFromShorthand(fromFlag)
+	}
+	if toFlag != "" {
+		to = util.TimeFromShorthand(toFlag)
+	}
+
+	for _, a := range model.GetAbsences(from, to) {
+		if absenceType != "" && a.Type != absenceType {
+			continue
+		}
+
+		numDays := a.GetNumDays()
+		s := "s"
+		if numDays == 1 {
+			s = ""
+		}
+
+		color.Printf(
+			view.AbsenceDatesTypeDays,
+			a.Id,
+			a.StartDate.Format("Mon Jan 02"),
+			a.EndDate.Format("Mon Jan 02 2006"),
+			a.Type,
+			numDays,
+			s,
+			a.Description,
+		)
+	}
+}
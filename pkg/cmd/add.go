@@ -6,10 +6,11 @@ import (
 
 	"github.com/gookit/color"
 	"github.com/jasonwoodland/track/pkg/completion"
-	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/hooks"
 	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/util"
 	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/jasonwoodland/track/pkg/webhook"
 	"github.com/urfave/cli/v2"
 )
 
@@ -24,6 +25,10 @@ var Add = &cli.Command{
 			Aliases: []string{"o"},
 			Usage:   "Duration to offset the frame by (eg. -o -5m will add a frame that finished 5 minutes ago)",
 		},
+		&cli.BoolFlag{
+			Name:  "new",
+			Usage: "Add a new task with the exact name given, even if it is similar to an existing task",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		if c.Args().Len() != 3 {
@@ -39,15 +44,17 @@ var Add = &cli.Command{
 			log.Fatalf("Bad duration: %s", c.Args().Get(2))
 		}
 
-		project := model.GetProjectByName(projectName)
+		project := findProject(projectName)
 		if project == nil {
-			color.Printf(view.ProjectDoesNotExist, projectName)
 			return nil
 		}
 
-		task := project.GetTask(taskName)
+		task, ok := findTaskOrNew(project, taskName, c.Bool("new"))
+		if !ok {
+			return nil
+		}
 		if task == nil {
-			color.Printf(view.AddedTask, taskName)
+			color.Printf(view.AddedTask, view.TaskName(taskName, ""))
 			task = project.AddTask(taskName)
 		}
 
@@ -59,17 +66,12 @@ var Add = &cli.Command{
 			endTime = endTime.Add(o)
 		}
 
-		db.Db.Exec(
-			"insert into frame (task_id, start_time, end_time) values ($1, $2, $3)",
-			task.Id,
-			startTime.Format(time.RFC3339),
-			endTime.Format(time.RFC3339),
-		)
+		frame := model.AddFrame(task, startTime, endTime)
 
 		color.Printf(
 			view.AddedProjectTaskDurationTotal,
-			project.Name,
-			task.Name,
+			projectTag(project),
+			taskTag(task),
 			util.GetHours(duration),
 			util.GetHours(task.GetTotal()),
 		)
@@ -81,6 +83,8 @@ var Add = &cli.Command{
 			endTime.Format("15:04"),
 			util.GetHours(endTime.Sub(startTime)),
 		)
+		runPostHook(hooks.PostEdit, hooks.ActionAdd, frame)
+		webhook.NotifyGoals(frame)
 		return nil
 	},
 }
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/util"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+var Balance = &cli.Command{
+	Name:  "balance",
+	Usage: "Display the overtime balance against contracted work hours",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "from",
+			Aliases: []string{"f"},
+			Usage:   "Start date (defaults to the balance-start setting)",
+		},
+		&cli.StringFlag{
+			Name:    "to",
+			Aliases: []string{"t"},
+			Usage:   "End date (defaults to yesterday, since today isn't over)",
+		},
+		&cli.BoolFlag{
+			Name:    "days",
+			Aliases: []string{"d"},
+			Usage:   "Show the balance for each day",
+		},
+		timezoneFlag,
+	},
+	Before: setTimezone,
+	Action: func(c *cli.Context) error {
+		settings := db.GetSettings()
+
+		// Today isn't over, so it would count as a full day of expected hours
+		// without the running frame
+		now := time.Now()
+		yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.Local)
+
+		from := settings.BalanceStart
+		to := yesterday
+		if v := c.String("from"); v != "" {
+			from = util.TimeFromShorthand(v)
+		}
+		if v := c.String("to"); v != "" {
+			to = util.TimeFromShorthand(v)
+		}
+		if to.After(yesterday) {
+			to = yesterday
+		}
+		if from.IsZero() {
+			color.Printf(view.BalanceStartRequired)
+			return nil
+		}
+		if from.After(to) {
+			color.Printf(view.BalanceTotal, getColoredBalance(0))
+			return nil
+		}
+
+		query := `
+			with recursive dates(date) as (
+				values(?)
+				union all
+				select date(date, '+1 day')
+				from dates
+				where date < ?
+			)
+			select
+				dates.date,
+				coalesce((
+					select
+						sum(` + db.ClippedSeconds("f", db.LocalDayStart("dates.date"), db.LocalDayEnd("dates.date")) + `)
+					from frame f
+					where
+						` + db.Overlaps("f", db.LocalDayStart("dates.date"), db.LocalDayEnd("dates.date")) + `
+				), 0) as total
+			from dates
+			order by dates.date
+		`
+
+		params := []interface{}{
+			from.Format("2006-01-02"),
+			to.Format("2006-01-02"),
+		}
+
+		rows, err := db.Db.Query(query, params...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer rows.Close()
+
+		// Holidays and leave don't count towards the expected hours
+		absences := model.GetAbsenceDays(from, to)
+
+		type week struct {
+			weekStart time.Time
+			start     time.Time
+			end       time.Time
+			actual    time.Duration
+			expected  time.Duration
+		}
+
+		var (
+			balance     time.Duration
+			currentWeek *week
+			showDays    = c.Bool("days")
+		)
+
+		printWeek := func(w *week) {
+			balance += w.actual - w.expected
+			color.Printf(
+				view.BalanceWeek,
+				w.start.Format("Mon Jan 02"),
+				w.end.Format("Mon Jan 02"),
+				util.GetHours(w.actual),
+				util.GetHours(w.expected),
+				getColoredBalance(w.actual-w.expected),
+				getColoredBalance(balance),
+			)
+			if showDays {
+				fmt.Println()
+			}
+		}
+
+		color.Printf(view.BalanceWeekHeader, "Week", "Actual", "Expected", "Diff", "Balance")
+
+		for rows.Next() {
+			var date string
+			var actual time.Duration
+			rows.Scan(&date, &actual)
+			actual *= time.Second
+
+			d, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+
+			expected := settings.WorkHours[d.Weekday()]
+			if a := absences[date]; a != nil && a.IsLeave() {
+				expected = 0
+			}
+
+			weekStart := util.StartOfWeek(d, settings.FirstDayOfWeek)
+			if currentWeek == nil || !currentWeek.weekStart.Equal(weekStart) {
+				if currentWeek != nil {
+					printWeek(currentWeek)
+				}
+				currentWeek = &week{weekStart: weekStart, start: d}
+			}
+			currentWeek.end = d
+			currentWeek.actual += actual
+			currentWeek.expected += expected
+
+			if showDays {
+				var absenceType string
+				if a := absences[date]; a != nil {
+					absenceType = a.Type
+				}
+				color.Printf(
+					view.BalanceDay,
+					d.Format("Mon Jan 02"),
+					util.GetHours(actual),
+					util.GetHours(expected),
+					getColoredBalance(actual-expected),
+					absenceType,
+				)
+			}
+		}
+		if currentWeek != nil {
+			printWeek(currentWeek)
+		}
+
+		if !showDays {
+			fmt.Println()
+		}
+		color.Printf(view.BalanceTotal, getColoredBalance(balance))
+		return nil
+	},
+}
+
+func getColoredBalance(d time.Duration) string {
+	s := fmt.Sprintf("%8s", util.GetSignedHours(d))
+	if d < 0 {
+		return color.Sprintf("<red>%s</>", s)
+	}
+	return color.Sprintf("<green>%s</>", s)
+}
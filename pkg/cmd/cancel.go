@@ -6,6 +6,7 @@ import (
 
 	"github.com/gookit/color"
 	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/hooks"
 	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/util"
 	"github.com/jasonwoodland/track/pkg/view"
@@ -21,8 +22,8 @@ var Cancel = &cli.Command{
 		if state.Running {
 			color.Printf(
 				view.CancelledProjectTaskDurationTotal,
-				state.Task.Project.Name,
-				state.Task.Name,
+				projectTag(state.Task.Project),
+				taskTag(&state.Task),
 				util.GetHours(state.TimeElapsed),
 				util.GetHours(state.Task.GetTotal()),
 			)
@@ -32,8 +33,18 @@ var Cancel = &cli.Command{
 				state.TimeElapsed.Round(time.Second),
 			)
 
+			frame := model.GetRunningFrame()
 			db.Db.Exec("delete from frame where end_time is null")
-			db.Db.Exec("delete from task where not exists (select 1 from frame where frame.task_id = task.id)")
+			// Tasks with subtasks are kept even if they have no frames
+			// themselves, since deleting them deletes their subtasks
+			db.Db.Exec(`
+				delete from task
+				where
+					not exists (select 1 from frame where frame.task_id = task.id)
+				and
+					not exists (select 1 from task t where t.parent_id = task.id)
+			`)
+			runPostHook(hooks.PostCancel, hooks.ActionCancel, frame)
 		} else {
 			fmt.Println("Not runnning")
 		}
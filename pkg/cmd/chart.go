@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/util"
+)
+
+// Width of bar charts, using the space left in the terminal after the given
+// number of columns of other output
+func getChartWidth(used int) int {
+	width := util.TerminalWidth() - used
+	if width < 10 {
+		return 10
+	}
+	if width > 40 {
+		return 40
+	}
+	return width
+}
+
+// Colored bar for d relative to max, padded to width columns
+func getBar(d, max time.Duration, width int, colorName string) string {
+	return color.Sprintf("<%s>%s</>", colorName, util.Bar(d, max, width))
+}
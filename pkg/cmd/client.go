@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/completion"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/presenter"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+var ClientCmds = &cli.Command{
+	Name:         "client",
+	Usage:        "Manage clients which projects are billed to",
+	BashComplete: completion.CommandCompletion,
+	Subcommands: []*cli.Command{
+		{
+			Name:      "add",
+			Usage:     "Add a new client",
+			ArgsUsage: "name",
+			Action: func(c *cli.Context) error {
+				name := c.Args().Get(0)
+				if name == "" {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+				if model.GetClientByName(name) != nil {
+					color.Printf(view.ClientAlreadyExists, name)
+					return nil
+				}
+				model.AddClient(name)
+				color.Printf(view.AddedClient, name)
+				return nil
+			},
+		},
+		{
+			Name:         "rename",
+			Usage:        "Rename a client",
+			ArgsUsage:    "old_name new_name",
+			BashComplete: completion.ClientCompletion,
+			Action: func(c *cli.Context) error {
+				oldName := c.Args().Get(0)
+				newName := c.Args().Get(1)
+				if oldName == "" || newName == "" {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+				client := findClient(oldName)
+				if client == nil {
+					return nil
+				}
+				if model.GetClientByName(newName) != nil {
+					color.Printf(view.ClientAlreadyExists, newName)
+					return nil
+				}
+				oldName = client.Name
+				client.Rename(newName)
+				color.Printf(view.RenamedClient, oldName, newName)
+				return nil
+			},
+		},
+		{
+			Name:         "remove",
+			Aliases:      []string{"rm"},
+			Usage:        "Delete a client, keeping its projects",
+			ArgsUsage:    "name",
+			BashComplete: completion.ClientCompletion,
+			Action: func(c *cli.Context) error {
+				name := c.Args().Get(0)
+				if name == "" {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				client := findClient(name)
+				if client == nil {
+					return nil
+				}
+
+				numProjects := len(client.GetProjects())
+				s := "s"
+				if numProjects == 1 {
+					s = ""
+				}
+
+				if !presenter.Confirm(color.Sprintf(view.ConfirmDeleteClient, client.Name, numProjects, s), false) {
+					return nil
+				}
+
+				client.Remove()
+				color.Printf(view.DeletedClient, client.Name)
+				return nil
+			},
+		},
+		{
+			Name:    "list",
+			Aliases: []string{"ls"},
+			Usage:   "List clients and their projects",
+			Action: func(c *cli.Context) error {
+				for _, client := range model.GetClients() {
+					color.Printf(view.ClientProjects, client.Name, joinProjectNames(client.GetProjects()))
+				}
+				return nil
+			},
+		},
+	},
+}
+
+var clientFlag = &cli.StringFlag{
+	Name:    "client",
+	Aliases: []string{"c"},
+	Usage:   "Only include projects billed to a client",
+}
+
+// The clients of the projects by their id, for grouping projects by client
+func getClientNames() map[int64]string {
+	names := make(map[int64]string)
+	for _, c := range model.GetClients() {
+		names[c.Id] = c.Name
+	}
+	return names
+}
+
+// Resolve the --client flag, returning false if it's set but doesn't match
+// exactly one client
+func getClientFlag(c *cli.Context) (*model.Client, bool) {
+	name := strings.TrimSpace(c.String("client"))
+	if name == "" {
+		return nil, true
+	}
+	client := findClient(name)
+	return client, client != nil
+}
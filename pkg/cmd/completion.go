@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+
+	scripts "github.com/jasonwoodland/track/completion"
+	"github.com/jasonwoodland/track/pkg/completion"
+	"github.com/urfave/cli/v2"
+)
+
+var Completion = &cli.Command{
+	Name:         "completion",
+	Usage:        "Print the completion script for a shell",
+	ArgsUsage:    "bash|zsh|fish",
+	BashComplete: completion.ShellCompletion,
+	Action: func(c *cli.Context) error {
+		switch c.Args().Get(0) {
+		case "bash":
+			fmt.Print(scripts.Bash)
+		case "zsh":
+			fmt.Print(scripts.Zsh)
+		case "fish":
+			fmt.Print(scripts.Fish)
+		default:
+			cli.ShowSubcommandHelp(c)
+		}
+		return nil
+	},
+}
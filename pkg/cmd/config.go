@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"log"
+	"sort"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/completion"
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+func configKeyCompletion(c *cli.Context) {
+	if c.NArg() == 0 {
+		for _, k := range getConfigKeys() {
+			color.Println(k)
+		}
+	}
+}
+
+func getConfigKeys() (keys []string) {
+	for k := range db.ConfigKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return
+}
+
+var ConfigCmds = &cli.Command{
+	Name:  "config",
+	Usage: "Manage settings",
+	Action: func(c *cli.Context) error {
+		for _, k := range getConfigKeys() {
+			color.Printf(view.ConfigKeyValue, k, db.GetSetting(db.ConfigKeys[k]))
+		}
+		return nil
+	},
+	BashComplete: completion.CommandCompletion,
+	Subcommands: []*cli.Command{
+		{
+			Name:         "get",
+			Usage:        "Display a setting",
+			ArgsUsage:    "key",
+			BashComplete: configKeyCompletion,
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 1 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				name := c.Args().Get(0)
+				key, ok := db.ConfigKeys[name]
+				if !ok {
+					color.Printf(view.ConfigKeyDoesNotExist, name)
+					return nil
+				}
+
+				color.Println(db.GetSetting(key))
+				return nil
+			},
+		},
+		{
+			Name:         "set",
+			Usage:        "Change a setting",
+			ArgsUsage:    "key value",
+			BashComplete: configKeyCompletion,
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 2 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				name := c.Args().Get(0)
+				value := c.Args().Get(1)
+				key, ok := db.ConfigKeys[name]
+				if !ok {
+					color.Printf(view.ConfigKeyDoesNotExist, name)
+					return nil
+				}
+
+				if err := db.UpdateSetting(key, value); err != nil {
+					log.Fatalf("Bad value for %s: %s", name, err)
+				}
+
+				color.Printf(view.ConfigKeyValue, name, value)
+				return nil
+			},
+		},
+		{
+			Name:         "unset",
+			Usage:        "Reset a setting to its default",
+			ArgsUsage:    "key",
+			BashComplete: configKeyCompletion,
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 1 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				name := c.Args().Get(0)
+				key, ok := db.ConfigKeys[name]
+				if !ok {
+					color.Printf(view.ConfigKeyDoesNotExist, name)
+					return nil
+				}
+
+				db.DeleteSetting(key)
+				color.Printf(view.ConfigKeyUnset, name)
+				return nil
+			},
+		},
+	},
+}
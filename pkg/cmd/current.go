@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/completion"
 	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/hooks"
 	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/util"
 	"github.com/jasonwoodland/track/pkg/view"
@@ -21,9 +23,10 @@ const (
 )
 
 var Shift = &cli.Command{
-	Name:    "current",
-	Aliases: []string{"cur"},
-	Usage:   "Adjust the current running task",
+	Name:         "current",
+	Aliases:      []string{"cur"},
+	Usage:        "Adjust the current running task",
+	BashComplete: completion.CommandCompletion,
 	Subcommands: []*cli.Command{
 		{
 			Name:      "add",
@@ -69,7 +72,7 @@ func actionForCommand(cmd command) func(*cli.Context) error {
 
 		res, err := db.Db.Exec(
 			"update frame set start_time = $1 where end_time is null",
-			newStartTime.Format(time.RFC3339),
+			db.FormatTime(newStartTime),
 		)
 		if err != nil {
 			log.Fatal(err)
@@ -83,8 +86,8 @@ func actionForCommand(cmd command) func(*cli.Context) error {
 
 		color.Printf(
 			view.RunningProjectTaskPrevElapsedTotal,
-			state.Task.Project.Name,
-			state.Task.Name,
+			projectTag(state.Task.Project),
+			taskTag(&state.Task),
 			util.GetHours(prevTimeElapsed),
 			util.GetHours(state.TimeElapsed),
 			util.GetHours(prevTaskTotal),
@@ -97,6 +100,7 @@ func actionForCommand(cmd command) func(*cli.Context) error {
 			prevTimeElapsed.Round(time.Second),
 			state.TimeElapsed.Round(time.Second),
 		)
+		runPostHook(hooks.PostEdit, hooks.ActionEdit, model.GetRunningFrame())
 
 		return nil
 	}
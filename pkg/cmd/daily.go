@@ -8,9 +8,7 @@ import (
 	"github.com/gookit/color"
 	"github.com/jasonwoodland/track/pkg/completion"
 	"github.com/jasonwoodland/track/pkg/db"
-	"github.com/jasonwoodland/track/pkg/mytime"
-
-	// "github.com/jasonwoodland/track/pkg/mytime"
+	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/util"
 	"github.com/jasonwoodland/track/pkg/view"
 	"github.com/urfave/cli/v2"
@@ -33,14 +31,21 @@ var Daily = &cli.Command{
 			Aliases: []string{"t"},
 			Usage:   "End date",
 		},
+		&cli.BoolFlag{
+			Name:  "spark",
+			Usage: "Show a sparkline of the time spent each day",
+		},
+		clientFlag,
+		timezoneFlag,
 	},
+	Before: setTimezone,
 	Action: func(c *cli.Context) error {
 		// showFrames := c.Bool("frames")
 
 		from := time.Time{}
 		to := time.Now()
 		if v := c.String("from"); v != "" {
-			from = util.TimeFromShorthand(v).Add(-24 * time.Hour)
+			from = util.TimeFromShorthand(v)
 		} else {
 			log.Fatalln("from flag required")
 		}
@@ -50,60 +55,90 @@ var Daily = &cli.Command{
 			to = time.Now()
 		}
 
+		client, ok := getClientFlag(c)
+		if !ok {
+			return nil
+		}
+
+		// Only include frames on projects billed to the client
+		clientFilter := ""
+		if client != nil {
+			clientFilter = `
+				join task ct on ct.id = f.task_id
+				join project cp on cp.id = ct.project_id
+				where cp.client_id = ?
+			`
+		}
+
+		// Frames are split at midnight, so each day only includes the part of
+		// the frame which falls on that day
 		query := `
 			with recursive dates(date) as (
 				values(?)
 				union all
-				select strftime("%Y-%m-%dT%H:%M:%SZ", date(date, '+1 day'))
+				select date(date, '+1 day')
 				from dates
 				where date < ?
+			),
+			days(date, day_start, day_end) as (
+				select date, ` + db.LocalDayStart("date") + `, ` + db.LocalDayEnd("date") + `
+				from dates
+			),
+			clipped(date, task_id, seconds) as (
+				select days.date, f.task_id, ` + db.ClippedSeconds("f", "day_start", "day_end") + `
+				from days
+				join frame f on ` + db.Overlaps("f", "day_start", "day_end") + `
+				` + clientFilter + `
 			)
 			select
-				dates.date,
-		        	p.name,
-		        	t.name,
-		        	(
-					select
-						sum(strftime("%s", f2.end_time) - strftime("%s", f2.start_time))
-					from frame f2
-					where
-						strftime('%Y-%m-%d', f2.end_time) = strftime('%Y-%m-%d', dates.date)
+				days.date,
+				coalesce(cl.name, ''),
+				p.name,
+				tp.path,
+				coalesce(p.color, ''),
+				coalesce(t.color, ''),
+				(
+					select sum(c2.seconds)
+					from clipped c2
+					where c2.date = days.date
 				) as total,
-		                (
-		                	select
-		                		sum(strftime("%s", f2.end_time) - strftime("%s", f2.start_time)) as total
-		                	from frame f2
-		                	where
-		                		f2.task_id = t.id
-		                	and
-						strftime('%Y-%m-%d', f2.end_time) = strftime('%Y-%m-%d', dates.date)
-		                	and
-		                		f2.end_time not like '0001-%'
-		                ) as task_total,
-		                (
-		                	select
-		                		sum(strftime("%s", f2.end_time) - strftime("%s", f2.start_time)) as total
-		                	from frame f2
-		                	left join task t2 on t2.id = f2.task_id
-		                	where
-		                		t2.project_id = p.id
-		                	and
-						strftime('%Y-%m-%d', f2.end_time) = strftime('%Y-%m-%d', dates.date)
-		                	and
-		                		f2.end_time not like '0001-%'
-		                ) as project_total
-			from dates
-			left join frame f on strftime('%Y-%m-%d', f.end_time) = strftime('%Y-%m-%d', dates.date)
-			left join task t on t.id = f.task_id
+				sum(c.seconds) as task_total,
+				(
+					select sum(c2.seconds)
+					from clipped c2
+					left join task t2 on t2.id = c2.task_id
+					where
+						t2.project_id = p.id
+					and
+						c2.date = days.date
+				) as project_total,
+				(
+					select sum(c2.seconds)
+					from clipped c2
+					left join task t2 on t2.id = c2.task_id
+					left join project p2 on p2.id = t2.project_id
+					where
+						p2.client_id = p.client_id
+					and
+						c2.date = days.date
+				) as client_total
+			from days
+			left join clipped c on c.date = days.date
+			left join task t on t.id = c.task_id
+			left join task_path tp on tp.id = t.id
 			left join project p on p.id = t.project_id
-			group by t.id, dates.date
-			order by dates.date
+			left join client cl on cl.id = p.client_id
+			group by t.id, days.date
+			order by days.date, coalesce(cl.name, ''), p.name
 		`
 
 		params := []interface{}{
 			from.Format("2006-01-02"),
 			to.Format("2006-01-02"),
 		}
+		if client != nil {
+			params = append(params, client.Id)
+		}
 
 		rows, err := db.Db.Query(query, params...)
 		if err != nil {
@@ -111,32 +146,47 @@ var Daily = &cli.Command{
 		}
 		var prevDate time.Time
 		var prevProj string
+		var prevClient string
+
+		absences := model.GetAbsenceDays(from, to)
 
 		type row struct {
 			date            time.Time
+			clientName      string
 			projectName     string
 			taskName        string
+			projectColor    string
+			taskColor       string
 			totalDuration   time.Duration
 			taskDuration    time.Duration
 			projectDuration time.Duration
+			clientDuration  time.Duration
 		}
 
 		var totalDuration time.Duration
+		var dayTotals []time.Duration
 
 		for rows.Next() {
 			r := row{}
+			var date string
 			rows.Scan(
-				(*mytime.Time)(&r.date),
+				&date,
+				&r.clientName,
 				&r.projectName,
 				&r.taskName,
+				&r.projectColor,
+				&r.taskColor,
 				&r.totalDuration,
 				&r.taskDuration,
 				&r.projectDuration,
+				&r.clientDuration,
 			)
 
+			r.date, _ = time.ParseInLocation("2006-01-02", date, time.Local)
 			r.totalDuration *= time.Second
 			r.taskDuration *= time.Second
 			r.projectDuration *= time.Second
+			r.clientDuration *= time.Second
 			dateFmt := "Mon Jan 02"
 
 			if prevDate != r.date {
@@ -145,13 +195,37 @@ var Daily = &cli.Command{
 				}
 				prevDate = r.date
 				prevProj = ""
+				prevClient = ""
+				totalDuration += r.totalDuration
+				dayTotals = append(dayTotals, r.totalDuration)
+				if a := absences[r.date.Format("2006-01-02")]; a != nil {
+					annotation := a.Type
+					if a.Description != "" {
+						annotation += ": " + a.Description
+					}
+					color.Printf(
+						view.DailyDateHoursAbsence,
+						r.date.Format(dateFmt),
+						util.GetHours(r.totalDuration),
+						annotation,
+					)
+				} else {
+					color.Printf(
+						view.DailyDateHours,
+						r.date.Format(dateFmt),
+						util.GetHours(r.totalDuration),
+					)
+				}
+			}
+
+			if r.clientName != "" && prevClient != r.clientName {
+				prevClient = r.clientName
 				color.Printf(
-					view.DailyDateHours,
-					r.date.Format(dateFmt),
-					util.GetHours(r.totalDuration),
+					view.DailyHoursClient,
+					util.GetHours(r.clientDuration),
+					r.clientName,
 				)
 			}
-
 			if r.projectName != "" && prevProj != r.projectName {
 				// if prevProj != "" && prevProj != r.projectName {
 				// 	color.Println()
@@ -160,21 +234,29 @@ var Daily = &cli.Command{
 				color.Printf(
 					view.DailyHoursProject,
 					util.GetHours(r.projectDuration),
-					r.projectName,
+					view.ProjectName(r.projectName, r.projectColor),
 				)
 			}
 			if r.taskName != "" {
 				color.Printf(
 					view.DailyHoursTask,
 					util.GetHours(r.taskDuration),
+					view.TaskColor(r.taskColor),
 					50,
 					r.taskName,
 				)
 			}
-			totalDuration += r.totalDuration
 		}
 		fmt.Println()
 		fmt.Printf(view.TotalHours, totalDuration.Hours())
+		if c.Bool("spark") {
+			color.Printf(
+				view.DailySparkline,
+				prevDate.AddDate(0, 0, 1-len(dayTotals)).Format("Jan 02"),
+				util.Sparkline(dayTotals),
+				prevDate.Format("Jan 02"),
+			)
+		}
 		return nil
 	},
 }
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/util"
+	"github.com/jasonwoodland/track/pkg/view"
+)
+
+// Percentage of an estimate or budget after which we start warning
+const estimateWarnPercent = 80
+
+// Format tracked time against an estimate, eg. "6.00h of 8.00h (75%)"
+func getUsage(total, estimate time.Duration) string {
+	percent := util.GetPercent(total, estimate)
+	format := view.EstimateUsage
+	if percent >= 100 {
+		format = view.EstimateUsageOver
+	} else if percent >= estimateWarnPercent {
+		format = view.EstimateUsageWarn
+	}
+	return color.Sprintf(format, util.GetHours(total), util.GetHours(estimate), percent)
+}
+
+// Print a warning if the task or its project has used most of its estimate
+// or budget
+func printEstimateWarnings(t *model.Task) {
+	if t.Estimate != 0 {
+		percent := util.GetPercent(t.GetTotal(), t.Estimate)
+		if percent >= 100 {
+			color.Printf(view.WarnTaskOverEstimate, taskTag(t), percent, util.GetHours(t.Estimate))
+		} else if percent >= estimateWarnPercent {
+			color.Printf(view.WarnTaskEstimateUsed, taskTag(t), percent, util.GetHours(t.Estimate))
+		}
+	}
+
+	if t.Project != nil && t.Project.Budget != 0 {
+		percent := util.GetPercent(t.Project.GetTotal(), t.Project.Budget)
+		if percent >= 100 {
+			color.Printf(view.WarnProjectOverBudget, projectTag(t.Project), percent, util.GetHours(t.Project.Budget))
+		} else if percent >= estimateWarnPercent {
+			color.Printf(view.WarnProjectBudgetUsed, projectTag(t.Project), percent, util.GetHours(t.Project.Budget))
+		}
+	}
+}
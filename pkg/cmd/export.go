@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/completion"
+	"github.com/jasonwoodland/track/pkg/ical"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/util"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+var Export = &cli.Command{
+	Name:         "export",
+	Usage:        "Export frames as calendar events",
+	ArgsUsage:    "[project] [task]",
+	BashComplete: completion.ProjectTaskCompletion,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Format to export (ics)",
+			Value: "ics",
+		},
+		&cli.StringFlag{
+			Name:    "from",
+			Aliases: []string{"f"},
+			Usage:   "Start date from which to include frames",
+		},
+		&cli.StringFlag{
+			Name:    "to",
+			Aliases: []string{"t"},
+			Usage:   "End date from which to include frames",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if format := c.String("format"); format != "ics" {
+			color.Printf(view.BadFormat, format, "ics")
+			return nil
+		}
+
+		from := time.Time{}
+		to := time.Now()
+		if v := c.String("from"); v != "" {
+			from = util.TimeFromShorthand(v)
+		}
+		if v := c.String("to"); v != "" {
+			to = util.TimeFromShorthand(v)
+		}
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+		to = time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, time.Local)
+
+		var events []*ical.Event
+		for _, f := range model.GetFrames(from, to) {
+			// Running frames are exported once they've been stopped
+			if f.IsRunning() {
+				continue
+			}
+			if !strings.Contains(f.Task.Project.Name, c.Args().Get(0)) || !strings.Contains(f.Task.Name, c.Args().Get(1)) {
+				continue
+			}
+			events = append(events, &ical.Event{
+				UID:         "frame-" + strconv.FormatInt(f.Id, 10) + "@track",
+				Summary:     f.Task.Project.Name + ": " + f.Task.Name,
+				Description: f.Note,
+				Categories:  []string{f.Task.Project.Name},
+				Start:       f.StartTime,
+				End:         f.EndTime,
+			})
+		}
+
+		if err := ical.Write(os.Stdout, events); err != nil {
+			log.Fatal(err)
+		}
+		return nil
+	},
+}
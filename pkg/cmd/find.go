@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/presenter"
+	"github.com/jasonwoodland/track/pkg/view"
+)
+
+// Resolve a project from a name, which may be a unique prefix or fuzzy match
+// of the project name. Prints an error and returns nil if no project or more
+// than one project matches.
+func findProject(name string) *model.Project {
+	projects := model.FindProjects(name)
+	switch len(projects) {
+	case 1:
+		return projects[0]
+	case 0:
+		color.Printf(view.ProjectDoesNotExist, name)
+		if similar := model.SimilarProjects(name); len(similar) != 0 {
+			color.Printf(view.DidYouMean, joinProjectNames(similar))
+		}
+	default:
+		color.Printf(view.ProjectAmbiguous, name, joinProjectNames(projects))
+	}
+	return nil
+}
+
+// Resolve a project from its exact name, for commands which delete or merge
+// data, so a prefix or fuzzy match can't pick the wrong project. Prints the
+// projects the name would otherwise match if it isn't exact.
+func findExactProject(name string) *model.Project {
+	if p := model.GetProjectByName(name); p != nil {
+		return p
+	}
+	color.Printf(view.ProjectDoesNotExist, name)
+	similar := model.FindProjects(name)
+	if len(similar) == 0 {
+		similar = model.SimilarProjects(name)
+	}
+	if len(similar) != 0 {
+		color.Printf(view.DidYouMean, joinProjectNames(similar))
+	}
+	return nil
+}
+
+// Resolve a project like findProject, for commands which start tasks.
+// Archived projects are ignored, unless the name is an archived project's.
+func findActiveProject(name string) *model.Project {
+	if p := model.GetProjectByName(name); p != nil && p.Archived {
+		color.Printf(view.ProjectIsArchived, projectTag(p))
+		return nil
+	}
+	projects := model.ActiveProjects(model.FindProjects(name))
+	switch len(projects) {
+	case 1:
+		return projects[0]
+	case 0:
+		color.Printf(view.ProjectDoesNotExist, name)
+		if similar := model.ActiveProjects(model.SimilarProjects(name)); len(similar) != 0 {
+			color.Printf(view.DidYouMean, joinProjectNames(similar))
+		}
+	default:
+		color.Printf(view.ProjectAmbiguous, name, joinProjectNames(projects))
+	}
+	return nil
+}
+
+// Resolve a task on the project from a name, which may be a unique prefix or
+// fuzzy match of the task name. Prints an error and returns nil if no task or
+// more than one task matches.
+func findTask(project *model.Project, name string) *model.Task {
+	tasks := project.FindTasks(name)
+	switch len(tasks) {
+	case 1:
+		return tasks[0]
+	case 0:
+		color.Printf(view.TaskDoesNotExistForProject, view.TaskName(name, ""), projectTag(project))
+		if similar := project.SimilarTasks(name); len(similar) != 0 {
+			color.Printf(view.DidYouMean, joinTaskNames(similar))
+		}
+	default:
+		color.Printf(view.TaskAmbiguousForProject, view.TaskName(name, ""), projectTag(project), joinTaskNames(tasks))
+	}
+	return nil
+}
+
+// Resolve a task on the project from its exact name, like findExactProject
+func findExactTask(project *model.Project, name string) *model.Task {
+	if t := project.GetTask(name); t != nil {
+		return t
+	}
+	color.Printf(view.TaskDoesNotExistForProject, view.TaskName(name, ""), projectTag(project))
+	similar := project.FindTasks(name)
+	if len(similar) == 0 {
+		similar = project.SimilarTasks(name)
+	}
+	if len(similar) != 0 {
+		color.Printf(view.DidYouMean, joinTaskNames(similar))
+	}
+	return nil
+}
+
+// Resolve a task on the project like findTask, for commands which add the
+// task if it doesn't exist. Only exact and prefix matches are used, so a new
+// task isn't mistaken for an existing task with a fuzzy match. Returns a nil
+// task and true if a new task should be added. Unless force is set, adding a
+// task with a name similar to an existing task needs to be confirmed, so typos
+// don't create duplicate tasks. With force set the name must match exactly.
+// Archived tasks are ignored, unless the name is an archived task's.
+func findTaskOrNew(project *model.Project, name string, force bool) (*model.Task, bool) {
+	if t := project.GetTask(name); t != nil && t.Archived {
+		color.Printf(view.TaskIsArchivedForProject, taskTag(t), projectTag(project))
+		return nil, false
+	}
+
+	if force {
+		return project.GetTask(name), true
+	}
+
+	tasks := model.ActiveTasks(project.FindTasksByPrefix(name))
+	switch len(tasks) {
+	case 1:
+		return tasks[0], true
+	case 0:
+		if similar := model.ActiveTasks(project.SimilarTasks(name)); len(similar) != 0 {
+			return nil, presenter.Confirm(color.Sprintf(view.ConfirmAddSimilarTaskOnProject, view.TaskName(name, ""), joinTaskNames(similar), projectTag(project)), false)
+		}
+		return nil, true
+	default:
+		color.Printf(view.TaskAmbiguousForProject, view.TaskName(name, ""), projectTag(project), joinTaskNames(tasks))
+		return nil, false
+	}
+}
+
+// Resolve a client from a name, like findProject
+func findClient(name string) *model.Client {
+	clients := model.FindClients(name)
+	switch len(clients) {
+	case 1:
+		return clients[0]
+	case 0:
+		color.Printf(view.ClientDoesNotExist, name)
+		if similar := model.SimilarClients(name); len(similar) != 0 {
+			color.Printf(view.DidYouMean, joinClientNames(similar))
+		}
+	default:
+		color.Printf(view.ClientAmbiguous, name, joinClientNames(clients))
+	}
+	return nil
+}
+
+func joinProjectNames(projects []*model.Project) string {
+	var names []string
+	for _, p := range projects {
+		names = append(names, color.Sprintf(projectTag(p)))
+	}
+	return strings.Join(names, ", ")
+}
+
+func joinTaskNames(tasks []*model.Task) string {
+	var names []string
+	for _, t := range tasks {
+		names = append(names, color.Sprintf(taskTag(t)))
+	}
+	return strings.Join(names, ", ")
+}
+
+func joinClientNames(clients []*model.Client) string {
+	var names []string
+	for _, c := range clients {
+		names = append(names, color.Sprintf("<cyan>%s</>", c.Name))
+	}
+	return strings.Join(names, ", ")
+}
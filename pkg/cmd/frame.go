@@ -7,7 +7,7 @@ import (
 
 	"github.com/gookit/color"
 	"github.com/jasonwoodland/track/pkg/completion"
-	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/hooks"
 	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/presenter"
 	"github.com/jasonwoodland/track/pkg/util"
@@ -16,8 +16,9 @@ import (
 )
 
 var FrameCmds = &cli.Command{
-	Name:  "frame",
-	Usage: "Manage recorded frames for a task",
+	Name:         "frame",
+	Usage:        "Manage recorded frames for a task",
+	BashComplete: completion.CommandCompletion,
 	Subcommands: []*cli.Command{
 		{
 			Name:         "edit",
@@ -35,6 +36,11 @@ var FrameCmds = &cli.Command{
 					Aliases: []string{"e"},
 					Usage:   "Duration to modify the end time by (eg. --end -5m)",
 				},
+				&cli.StringFlag{
+					Name:    "note",
+					Aliases: []string{"n"},
+					Usage:   "Set a note for the frame",
+				},
 			},
 			Action: func(c *cli.Context) error {
 				if c.Args().Len() != 3 {
@@ -46,21 +52,19 @@ var FrameCmds = &cli.Command{
 				taskName := c.Args().Get(1)
 				frameIndex, _ := strconv.Atoi(c.Args().Get(2))
 
-				project := model.GetProjectByName(projectName)
+				project := findProject(projectName)
 				if project == nil {
-					color.Printf(view.ProjectDoesNotExist, projectName)
 					return nil
 				}
 
-				task := project.GetTask(taskName)
+				task := findTask(project, taskName)
 				if task == nil {
-					color.Printf(view.TaskDoesNotExistForProject, taskName, projectName)
 					return nil
 				}
 
 				frames := task.GetFrames()
 				if frameIndex > len(frames) {
-					color.Printf(view.FrameDoesNotExistForProjectTask, frameIndex, projectName, taskName)
+					color.Printf(view.FrameDoesNotExistForProjectTask, frameIndex, projectTag(project), taskTag(task))
 					return nil
 				}
 
@@ -70,13 +74,22 @@ var FrameCmds = &cli.Command{
 					frame.StartTime = frame.StartTime.Add(d)
 				}
 
-				if d, err := time.ParseDuration(c.String("end")); err == nil {
+				if d, err := time.ParseDuration(c.String("end")); err == nil && !frame.IsRunning() {
 					frame.EndTime = frame.EndTime.Add(d)
 				}
 
+				if c.IsSet("note") {
+					frame.Note = c.String("note")
+				}
+
+				if msg := checkFrameTimes(frame); msg != "" {
+					color.Printf(msg)
+					return nil
+				}
+
 				// TODO 00:00 shown if the frame is currently running.
-				color.Printf(view.Project, projectName)
-				color.Printf(view.Task, taskName)
+				color.Printf(view.Project, projectTag(project))
+				color.Printf(view.Task, taskTag(task))
 				color.Printf(
 					view.FrameTimesDuration,
 					frameIndex,
@@ -84,13 +97,12 @@ var FrameCmds = &cli.Command{
 					frame.EndTime.Format("15:04"),
 					util.GetHours(frame.EndTime.Sub(frame.StartTime)),
 				)
+				if frame.Note != "" {
+					color.Printf(view.FrameNote, frame.Note)
+				}
 
-				db.Db.Exec(
-					"update frame set start_time = $1, end_time = $2 where id = $3",
-					frame.StartTime.Format(time.RFC3339),
-					frame.EndTime.Format(time.RFC3339),
-					frame.Id,
-				)
+				frame.Update()
+				runPostHook(hooks.PostEdit, hooks.ActionEdit, frame)
 				return nil
 			},
 		},
@@ -110,21 +122,19 @@ var FrameCmds = &cli.Command{
 				taskName := c.Args().Get(1)
 				frameIndex, _ := strconv.Atoi(c.Args().Get(2))
 
-				project := model.GetProjectByName(projectName)
+				project := findProject(projectName)
 				if project == nil {
-					color.Printf(view.ProjectDoesNotExist, projectName)
 					return nil
 				}
 
-				task := project.GetTask(taskName)
+				task := findTask(project, taskName)
 				if task == nil {
-					color.Printf(view.TaskDoesNotExistForProject, taskName, projectName)
 					return nil
 				}
 
 				frames := task.GetFrames()
 				if frameIndex > len(frames)-1 {
-					color.Printf(view.FrameDoesNotExistForProjectTask, frameIndex, projectName, taskName)
+					color.Printf(view.FrameDoesNotExistForProjectTask, frameIndex, projectTag(project), taskTag(task))
 					return nil
 				}
 
@@ -132,17 +142,14 @@ var FrameCmds = &cli.Command{
 					view.ConfirmDeleteFrameTimeProjectTask,
 					frames[frameIndex].StartTime.Format("Mon Jan 02 15:04"),
 					frames[frameIndex].EndTime.Format("15:04"),
-					projectName,
-					taskName,
+					projectTag(project),
+					taskTag(task),
 				), false) {
 					return nil
 				}
 
-				frame := frames[frameIndex]
-				db.Db.Exec(
-					"delete from frame where id = $1",
-					frame.Id,
-				)
+				frames[frameIndex].Remove()
+				runPostHook(hooks.PostEdit, hooks.ActionRemove, frames[frameIndex])
 
 				color.Println(view.Deleted)
 
@@ -167,34 +174,34 @@ var FrameCmds = &cli.Command{
 				newProjectName := c.Args().Get(3)
 				newTaskName := c.Args().Get(4)
 
-				project := model.GetProjectByName(projectName)
+				project := findProject(projectName)
 				if project == nil {
-					color.Printf(view.ProjectDoesNotExist, projectName)
 					return nil
 				}
 
-				task := project.GetTask(taskName)
+				task := findTask(project, taskName)
 				if task == nil {
-					color.Printf(view.TaskDoesNotExistForProject, taskName, projectName)
 					return nil
 				}
 
 				frames := task.GetFrames()
 				if frameIndex > len(frames)-1 {
-					color.Printf(view.FrameDoesNotExistForProjectTask, frameIndex, projectName, taskName)
+					color.Printf(view.FrameDoesNotExistForProjectTask, frameIndex, projectTag(project), taskTag(task))
 					return nil
 				}
 
-				newProject := model.GetProjectByName(newProjectName)
+				newProject := findProject(newProjectName)
 				if newProject == nil {
-					color.Printf(view.ProjectDoesNotExist, newProjectName)
 					return nil
 				}
 
-				newTask := newProject.GetTask(newTaskName)
-				if task == nil {
-					color.Printf(view.AddedTask, taskName)
-					task = project.AddTask(taskName)
+				newTask, ok := findTaskOrNew(newProject, newTaskName, false)
+				if !ok {
+					return nil
+				}
+				if newTask == nil {
+					color.Printf(view.AddedTask, view.TaskName(newTaskName, ""))
+					newTask = newProject.AddTask(newTaskName)
 				}
 
 				if !presenter.Confirm(
@@ -202,10 +209,10 @@ var FrameCmds = &cli.Command{
 						view.ConfirmMoveFrameTimesFromToProjectTask,
 						frames[frameIndex].StartTime.Format("Mon Jan 02 15:04"),
 						frames[frameIndex].EndTime.Format("Mon Jan 02"),
-						projectName,
-						taskName,
-						newProjectName,
-						newTaskName,
+						projectTag(project),
+						taskTag(task),
+						projectTag(newProject),
+						taskTag(newTask),
 					),
 					false,
 				) {
@@ -213,11 +220,9 @@ var FrameCmds = &cli.Command{
 				}
 
 				frame := frames[frameIndex]
-				db.Db.Exec(
-					"update frame set task_id = $1 where id = $2",
-					newTask.Id,
-					frame.Id,
-				)
+				frame.Task = newTask
+				frame.Update()
+				runPostHook(hooks.PostEdit, hooks.ActionMove, frame)
 
 				fmt.Println(view.Moved)
 
@@ -226,3 +231,17 @@ var FrameCmds = &cli.Command{
 		},
 	},
 }
+
+// Check an edited frame's times, returning the message to show if they're bad.
+// A stopped frame has to end after it starts, and a running frame can't start
+// in the future.
+func checkFrameTimes(f *model.Frame) string {
+	if f.IsRunning() {
+		if f.StartTime.After(time.Now()) {
+			return view.FrameStartsInFuture
+		}
+	} else if !f.EndTime.After(f.StartTime) {
+		return view.FrameEndsBeforeStart
+	}
+	return ""
+}
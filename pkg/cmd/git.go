@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/completion"
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/git"
+	"github.com/jasonwoodland/track/pkg/hooks"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/util"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+// The line the post-checkout hook runs, which is also used to tell if the
+// hook has already been installed
+const gitHookLine = `track git post-checkout "$@"`
+
+const gitHook = `#!/bin/sh
+# Switch the running task when checking out a branch
+` + gitHookLine + `
+`
+
+var GitCmds = &cli.Command{
+	Name:         "git",
+	Usage:        "Switch tasks with git branches",
+	BashComplete: completion.CommandCompletion,
+	Subcommands: []*cli.Command{
+		{
+			Name:  "install-hook",
+			Usage: "Install a post-checkout hook in the current repository which switches the running task with the branch",
+			Action: func(c *cli.Context) error {
+				dir, err := git.HooksDir(".")
+				if err != nil {
+					color.Printf(view.GitError, err)
+					return nil
+				}
+
+				path := filepath.Join(dir, "post-checkout")
+				if b, err := ioutil.ReadFile(path); err == nil {
+					if strings.Contains(string(b), gitHookLine) {
+						color.Printf(view.GitHookAlreadyInstalled, path)
+					} else {
+						color.Printf(view.GitHookExists, path, gitHookLine)
+					}
+					return nil
+				}
+
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					color.Printf(view.GitError, err)
+					return nil
+				}
+				if err := ioutil.WriteFile(path, []byte(gitHook), 0755); err != nil {
+					color.Printf(view.GitError, err)
+					return nil
+				}
+				color.Printf(view.InstalledGitHook, path)
+				return nil
+			},
+		},
+		{
+			Name:      "post-checkout",
+			Usage:     "Switch the running task to the checked out branch, run by the post-checkout hook",
+			ArgsUsage: "prev_head new_head branch_checkout",
+			Hidden:    true,
+			Action: func(c *cli.Context) error {
+				// Only switch when a branch is checked out, not files
+				if c.Args().Get(2) != "1" {
+					return nil
+				}
+
+				state := model.GetState()
+				if !state.Running {
+					return nil
+				}
+
+				projectName, taskName, err := gitProjectTask(".")
+				if err != nil {
+					return nil
+				}
+
+				project := findActiveProject(projectName)
+				if project == nil {
+					return nil
+				}
+
+				task, _ := findTaskOrNew(project, taskName, true)
+				if task != nil && task.Id == state.Task.Id {
+					return nil
+				}
+
+				now := time.Now()
+				hookTask := task
+				if hookTask == nil {
+					hookTask = &model.Task{Project: project, Name: taskName}
+				}
+				if !runPreHook(hooks.PreStart, hooks.ActionStart, &model.Frame{Task: hookTask, StartTime: now}) {
+					return nil
+				}
+
+				stopFrame(now)
+				color.Printf(
+					view.StoppedProjectTaskElapsedTotal,
+					projectTag(state.Task.Project),
+					taskTag(&state.Task),
+					util.GetHours(state.TimeElapsed),
+					util.GetHours(state.Task.GetTotal()),
+				)
+
+				task = startFrame(project, task, taskName, now)
+				color.Printf(
+					view.RunningProjectTaskTotal,
+					projectTag(project),
+					taskTag(task),
+					util.GetHours(task.GetTotal()),
+				)
+				return nil
+			},
+		},
+	},
+}
+
+// Find the project and task for the git repository in dir. The project is
+// the track.project git config, or the name of the repository. The task is
+// the branch, or the part of it matched by the track.taskPattern git config or
+// the git-task-pattern setting, with slashes replaced by dashes.
+func gitProjectTask(dir string) (projectName, taskName string, err error) {
+	topLevel, err := git.TopLevel(dir)
+	if err != nil {
+		return "", "", err
+	}
+	branch, err := git.Branch(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	projectName = git.Config(dir, "track.project")
+	if projectName == "" {
+		projectName = filepath.Base(topLevel)
+	}
+
+	pattern := git.Config(dir, "track.taskPattern")
+	if pattern == "" {
+		pattern = db.GetSettings().GitTaskPattern
+	}
+	taskName = branch
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", "", err
+		}
+		if m := re.FindStringSubmatch(branch); len(m) > 1 && m[1] != "" {
+			taskName = m[1]
+		} else if m != nil {
+			taskName = m[0]
+		}
+	}
+
+	// Slashes separate subtasks, so a branch like feature/login would add
+	// a feature task with a login subtask
+	taskName = strings.ReplaceAll(taskName, "/", "-")
+	return projectName, taskName, nil
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/completion"
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/util"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+// Glyphs and 256 color greens for each level of hours tracked in a day, from
+// nothing tracked to the most tracked
+var (
+	heatmapGlyphs = []string{"·", "░", "▒", "▓", "█"}
+	heatmapColors = []uint8{238, 22, 28, 34, 46}
+)
+
+var Heatmap = &cli.Command{
+	Name:         "heatmap",
+	Usage:        "Display a calendar heatmap of time tracked each day of the year",
+	ArgsUsage:    "[project] [task]",
+	BashComplete: completion.ProjectTaskCompletion,
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:    "year",
+			Aliases: []string{"y"},
+			Usage:   "Year to display (defaults to the current year)",
+		},
+		timezoneFlag,
+	},
+	Before: setTimezone,
+	Action: func(c *cli.Context) error {
+		year := time.Now().Year()
+		if v := c.Int("year"); v != 0 {
+			year = v
+		}
+
+		from := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+		to := time.Date(year, 12, 31, 0, 0, 0, 0, time.Local)
+
+		// Frames spanning midnight count towards each day they cover
+		query := `
+			with recursive dates(date) as (
+				values(?)
+				union all
+				select date(date, '+1 day')
+				from dates
+				where date < ?
+			)
+			select
+				dates.date,
+				coalesce((
+					select
+						sum(` + db.ClippedSeconds("f", db.LocalDayStart("dates.date"), db.LocalDayEnd("dates.date")) + `)
+					from frame f
+					left join task t on t.id = f.task_id
+					left join task_path tp on tp.id = t.id
+					left join project p on p.id = t.project_id
+					where
+						` + db.Overlaps("f", db.LocalDayStart("dates.date"), db.LocalDayEnd("dates.date")) + `
+					and
+						p.name like ?
+					and
+						tp.path like ?
+				), 0) as total
+			from dates
+			order by dates.date
+		`
+
+		params := []interface{}{
+			from.Format("2006-01-02"),
+			to.Format("2006-01-02"),
+			"%" + c.Args().Get(0) + "%",
+			"%" + c.Args().Get(1) + "%",
+		}
+
+		rows, err := db.Db.Query(query, params...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer rows.Close()
+
+		days := make(map[string]time.Duration)
+		var total, max time.Duration
+		var numDays int
+		for rows.Next() {
+			var date string
+			var d time.Duration
+			rows.Scan(&date, &d)
+			d *= time.Second
+			days[date] = d
+			total += d
+			if d > max {
+				max = d
+			}
+			if d > 0 {
+				numDays++
+			}
+		}
+
+		// Each column is a week, starting on the configured first day
+		firstDay := db.GetSettings().FirstDayOfWeek
+		start := util.StartOfWeek(from, firstDay)
+		numWeeks := int(math.Ceil(to.Sub(start).Hours()/24/7 + 1.0/7))
+
+		level := func(d time.Duration) int {
+			if d == 0 || max == 0 {
+				return 0
+			}
+			return int(math.Ceil(float64(d) / float64(max) * float64(len(heatmapGlyphs)-1)))
+		}
+
+		cell := func(l int) string {
+			if color.IsSupport256Color() {
+				return color.C256(heatmapColors[l]).Sprint(heatmapGlyphs[l])
+			}
+			if l == 0 {
+				return color.Sprintf("<gray>%s</>", heatmapGlyphs[l])
+			}
+			return color.Sprintf("<green>%s</>", heatmapGlyphs[l])
+		}
+
+		// Month labels above the first week containing the 1st of the month
+		labels := []byte(strings.Repeat(" ", numWeeks*2+3))
+		for m := time.January; m <= time.December; m++ {
+			first := time.Date(year, m, 1, 0, 0, 0, 0, time.Local)
+			week := int(first.Sub(start).Hours()/24+0.5) / 7
+			copy(labels[4+week*2:], first.Format("Jan"))
+		}
+		color.Printf("<gray>%s</>\n", strings.TrimRight(string(labels), " "))
+
+		for weekday := 0; weekday < 7; weekday++ {
+			name := time.Weekday((int(firstDay) + weekday) % 7).String()[:3]
+			if weekday%2 == 1 {
+				name = ""
+			}
+			color.Printf("<gray>%-3s</> ", name)
+
+			for week := 0; week < numWeeks; week++ {
+				d := start.AddDate(0, 0, week*7+weekday)
+				if d.Before(from) || d.After(to) {
+					fmt.Print("  ")
+					continue
+				}
+				fmt.Print(cell(level(days[d.Format("2006-01-02")])), " ")
+			}
+			fmt.Println()
+		}
+
+		fmt.Println()
+		color.Printf("<gray>%3s</> <gray>Less</> ", "")
+		for l := range heatmapGlyphs {
+			fmt.Print(cell(l), " ")
+		}
+		color.Printf("<gray>More</>  (max %s)\n", util.GetHours(max))
+
+		fmt.Println()
+		color.Printf(view.HeatmapTotalDays, total.Hours(), numDays, year)
+		return nil
+	},
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/hooks"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/jasonwoodland/track/pkg/webhook"
+)
+
+// Show a failed hook. The ui replaces it to show the failure in place of its
+// last message, since printing would draw over the dashboard.
+var showHookError = func(msg string, err error) {
+	color.Printf(msg, err)
+}
+
+// Run a pre-* hook, returning false if the hook failed and the command should
+// be aborted
+func runPreHook(hook, action string, f *model.Frame) bool {
+	if err := hooks.Run(hook, action, f); err != nil {
+		showHookError(view.HookAborted, err)
+		return false
+	}
+	return true
+}
+
+// Run a post-* hook, warning if the hook failed
+func runPostHook(hook, action string, f *model.Frame) {
+	if err := hooks.Run(hook, action, f); err != nil {
+		showHookError(view.HookFailed, err)
+	}
+}
+
+// Start a frame on the task, adding the task if it's nil, running the
+// post-start hook and notifying webhooks
+func startFrame(project *model.Project, task *model.Task, taskName string, startTime time.Time) *model.Task {
+	if task == nil {
+		color.Printf(view.AddedTask, view.TaskName(taskName, ""))
+		task = project.AddTask(taskName)
+	}
+	frame := model.StartFrame(task, startTime)
+	runPostHook(hooks.PostStart, hooks.ActionStart, frame)
+	webhook.Notify(webhook.EventStart, frame)
+	return task
+}
+
+// Stop the running frame, running the post-stop hook and notifying webhooks
+func stopFrame(endTime time.Time) bool {
+	f := model.GetRunningFrame()
+	if f == nil {
+		return false
+	}
+	model.StopFrame(endTime)
+	f.EndTime = endTime
+	runPostHook(hooks.PostStop, hooks.ActionStop, f)
+	webhook.Notify(webhook.EventStop, f)
+	webhook.NotifyGoals(f)
+	return true
+}
+
+// Run the post-edit hook for each of the frames
+func runPostEditHooks(action string, frames []*model.Frame) {
+	for _, f := range frames {
+		runPostHook(hooks.PostEdit, action, f)
+	}
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/hooks"
+	"github.com/jasonwoodland/track/pkg/ical"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/jasonwoodland/track/pkg/webhook"
+	"github.com/urfave/cli/v2"
+)
+
+// A rule mapping calendar events with a matching summary or category to a task
+type taskRule struct {
+	pattern *regexp.Regexp
+	task    string
+}
+
+var Import = &cli.Command{
+	Name:      "import",
+	Usage:     "Import calendar events as frames",
+	ArgsUsage: "file.ics",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Format to import (ics)",
+			Value: "ics",
+		},
+		&cli.StringFlag{
+			Name:    "project",
+			Aliases: []string{"p"},
+			Usage:   "Project to add the frames to",
+		},
+		&cli.StringSliceFlag{
+			Name:    "map",
+			Aliases: []string{"m"},
+			Usage:   "Add events whose summary or category matches a regexp to a task (eg. --map 'standup|scrum=standup'), can be given more than once",
+		},
+		&cli.StringFlag{
+			Name:    "task",
+			Aliases: []string{"t"},
+			Usage:   "Task for events which don't match a --map rule or have a category, instead of the event's summary",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Args().Len() != 1 || c.String("project") == "" {
+			cli.ShowSubcommandHelp(c)
+			return nil
+		}
+
+		if format := c.String("format"); format != "ics" {
+			color.Printf(view.BadFormat, format, "ics")
+			return nil
+		}
+
+		var rules []taskRule
+		for _, m := range c.StringSlice("map") {
+			i := strings.LastIndex(m, "=")
+			if i == -1 {
+				log.Fatalf("Bad rule: %s (expected pattern=task)", m)
+			}
+			pattern, err := regexp.Compile("(?i)" + m[:i])
+			if err != nil {
+				log.Fatalf("Bad rule: %s (%s)", m, err)
+			}
+			rules = append(rules, taskRule{pattern, m[i+1:]})
+		}
+
+		project := findProject(c.String("project"))
+		if project == nil {
+			return nil
+		}
+
+		f, err := os.Open(c.Args().Get(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+
+		events, err := ical.Parse(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		imported, updated, skipped := 0, 0, 0
+		for _, e := range events {
+			// Only import events which have finished, and skip all day
+			// events since they aren't time spent on anything
+			if e.UID == "" || e.AllDay || !e.End.After(e.Start) || e.End.After(time.Now()) {
+				skipped++
+				continue
+			}
+
+			uid := e.UID
+			if e.RecurrenceID != "" {
+				uid += "/" + e.RecurrenceID
+			}
+
+			// Events which have already been imported are only updated if
+			// they've moved
+			if frame := model.GetFrameByUid(uid); frame != nil {
+				if frame.StartTime.Equal(e.Start) && frame.EndTime.Equal(e.End) {
+					skipped++
+					continue
+				}
+				frame.StartTime = e.Start
+				frame.EndTime = e.End
+				frame.Update()
+				runPostHook(hooks.PostEdit, hooks.ActionEdit, frame)
+				updated++
+				continue
+			}
+
+			taskName := getEventTask(e, rules, c.String("task"))
+			if taskName == "" {
+				skipped++
+				continue
+			}
+			task, _ := findTaskOrNew(project, taskName, true)
+			if task == nil {
+				color.Printf(view.AddedTask, view.TaskName(taskName, ""))
+				task = project.AddTask(taskName)
+			}
+
+			frame := model.AddFrame(task, e.Start.Local(), e.End.Local())
+			frame.SetUid(uid)
+			if e.Description != "" {
+				frame.Note = e.Description
+				frame.Update()
+			}
+			runPostHook(hooks.PostEdit, hooks.ActionAdd, frame)
+			webhook.NotifyGoals(frame)
+			imported++
+		}
+
+		s := "s"
+		if imported == 1 {
+			s = ""
+		}
+		color.Printf(view.ImportedFramesUpdatedSkipped, imported, s, updated, skipped)
+		return nil
+	},
+}
+
+// The task for an event is the first rule matching its summary or one of its
+// categories, or its first category, or the default task or its summary
+func getEventTask(e *ical.Event, rules []taskRule, defaultTask string) string {
+	for _, r := range rules {
+		if r.pattern.MatchString(e.Summary) {
+			return r.task
+		}
+		for _, c := range e.Categories {
+			if r.pattern.MatchString(c) {
+				return r.task
+			}
+		}
+	}
+	if len(e.Categories) != 0 && strings.TrimSpace(e.Categories[0]) != "" {
+		return strings.TrimSpace(e.Categories[0])
+	}
+	if defaultTask != "" {
+		return defaultTask
+	}
+	return e.Summary
+}
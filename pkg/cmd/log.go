@@ -2,15 +2,14 @@ package cmd
 
 import (
 	"fmt"
-	"log"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/gookit/color"
 	"github.com/jasonwoodland/track/pkg/completion"
-	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/git"
 	"github.com/jasonwoodland/track/pkg/model"
-	"github.com/jasonwoodland/track/pkg/mytime"
 	"github.com/jasonwoodland/track/pkg/util"
 	"github.com/jasonwoodland/track/pkg/view"
 	"github.com/urfave/cli/v2"
@@ -37,9 +36,25 @@ var Log = &cli.Command{
 			Aliases: []string{"x"},
 			Usage:   "Show individual frames for each task",
 		},
+		&cli.StringFlag{
+			Name:  "git",
+			Usage: "Show the commits you authored in a git repository during each frame (implies --frames)",
+		},
+		&cli.BoolFlag{
+			Name:  "git-notes",
+			Usage: "Save the subjects of the commits as the note of frames without one, with --git",
+		},
+		&cli.BoolFlag{
+			Name:  "chart",
+			Usage: "Show a bar chart of the time spent on each project and task",
+		},
+		clientFlag,
+		timezoneFlag,
 	},
+	Before: setTimezone,
 	Action: func(c *cli.Context) error {
-		showFrames := c.Bool("frames")
+		showFrames := c.Bool("frames") || c.String("git") != ""
+		saveNotes := c.Bool("git-notes")
 
 		from := time.Time{}
 		to := time.Now()
@@ -50,150 +65,162 @@ var Log = &cli.Command{
 			to = util.TimeFromShorthand(v)
 		}
 
-		query := `
-			select
-				p.name,
-				t.name,
-				sum(strftime("%s", end_time) - strftime("%s", start_time)) as total,
-
-				-- We're only filtering by these max start_date/min end_date,
-				-- So if any frames are within the --from/--to flags, we inlcude the
-				-- in the results
-				min(start_time) as start_date,
-				max(end_time) as end_date,
-				(
-					select
-						sum(strftime("%s", f2.end_time) - strftime("%s", f2.start_time)) as total
-					from frame f2
-					where
-						f2.task_id = t.id
-					and
-						f2.end_time >= ?
-					and
-						f2.end_time <= ?
-					and
-						f2.end_time not like '0001-%'
-				) as task_total,
-				(
-					select
-						sum(strftime("%s", f2.end_time) - strftime("%s", f2.start_time)) as total
-					from frame f2
-					left join task t2 on t2.id = task_id
-					where
-						t2.project_id = p.id
-					and
-						f2.end_time >= ?
-					and
-						f2.end_time <= ?
-					and
-						f2.end_time not like '0001-%'
-				) as project_total
-			from frame f
-			left join task t on t.id = task_id
-			left join project p on p.id = t.project_id
-			where
-				f.end_time >= ?
-			and
-				f.end_time <= ?
-			group by task_id
-			`
-
-		params := []interface{}{
-			from.Format("2006-01-02"),
-			to.Format("2006-01-02"),
-			from.Format("2006-01-02"),
-			to.Format("2006-01-02"),
-			from.Format("2006-01-02"),
-			to.Format("2006-01-02"),
-		}
-
-		var whereConds []string
-
-		whereConds = append(whereConds, "f.end_time >= ?")
-		params = append(params, from.Format("2006-01-02"))
+		// Frames are clipped to the --from/--to range, so frames which only
+		// partially fall within the range only count the time inside it
+		rangeStart := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+		rangeEnd := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, time.Local)
 
-		whereConds = append(whereConds, "f.end_time <= ?")
-		params = append(params, to.Format("2006-01-02"))
-
-		if p := c.Args().Get(0); p != "" {
-			whereConds = append(whereConds, "p.name like ?")
-			params = append(params, "%"+p+"%")
+		// Commits are matched to frames by the time they were authored. Frames
+		// which start before the range can still have commits in it.
+		var commits []*git.Commit
+		if dir := c.String("git"); dir != "" {
+			if _, err := git.TopLevel(dir); err != nil {
+				color.Printf(view.GitError, err)
+				return nil
+			}
+			email := git.Config(dir, "user.email")
+			if email == "" {
+				color.Printf(view.GitError, "user.email isn't set")
+				return nil
+			}
+			var err error
+			commits, err = git.Commits(dir, email, rangeStart.AddDate(0, 0, -1))
+			if err != nil {
+				color.Printf(view.GitError, err)
+				return nil
+			}
 		}
+		savedNotes := 0
 
-		if t := c.Args().Get(1); t != "" {
-			whereConds = append(whereConds, "t.name like ?")
-			params = append(params, "%"+t+"%")
+		client, ok := getClientFlag(c)
+		if !ok {
+			return nil
 		}
 
-		// Add where conditions to query
-		if len(whereConds) != 0 {
-			query += "having\n" + strings.Join(whereConds, "\nand\n")
-		}
+		var (
+			totalDuration time.Duration
+			maxTask       time.Duration
+			maxProject    time.Duration
+			maxClient     time.Duration
+			prevProject   string
+			prevClient    int64
+		)
 
-		query += `
-			order by p.name, start_date
-		`
+		// The totals for usage of estimates and budgets are found at once
+		// rather than for each task and project
+		taskTotals := model.GetTaskTotals()
+		projectTotals := model.GetProjectTotals()
 
-		rows, err := db.Db.Query(query, params...)
-		if err != nil {
-			log.Fatal(err)
-		}
-		var prevProject string
-
-		type row struct {
-			projectName     string
-			taskName        string
-			totalDuration   time.Duration
-			startDate       time.Time
-			endDate         time.Time
-			taskDuration    time.Duration
-			projectDuration time.Duration
+		// Projects are grouped by client, after the projects without one
+		clientNames := getClientNames()
+		var entries []*model.LogEntry
+		for _, e := range model.GetLog(rangeStart, rangeEnd, c.Args().Get(0), c.Args().Get(1)) {
+			if client == nil || e.Task.Project.ClientId == client.Id {
+				entries = append(entries, e)
+			}
 		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return clientNames[entries[i].Task.Project.ClientId] < clientNames[entries[j].Task.Project.ClientId]
+		})
 
-		var totalDuration time.Duration
-
-		for rows.Next() {
-			r := row{}
-			rows.Scan(
-				&r.projectName,
-				&r.taskName,
-				&r.totalDuration,
-				(*mytime.Time)(&r.startDate),
-				(*mytime.Time)(&r.endDate),
-				&r.taskDuration,
-				&r.projectDuration,
-			)
+		var times []taskTime
+		clientDurations := make(map[int64]time.Duration)
+		for i, e := range entries {
+			totalDuration += e.Duration
+			if e.ProjectDuration > maxProject {
+				maxProject = e.ProjectDuration
+			}
+			if i == 0 || e.Task.Project.Id != entries[i-1].Task.Project.Id {
+				clientDurations[e.Task.Project.ClientId] += e.ProjectDuration
+			}
+			if clientDurations[e.Task.Project.ClientId] > maxClient {
+				maxClient = clientDurations[e.Task.Project.ClientId]
+			}
+			times = append(times, taskTime{e.Task, e.StartTime, e.EndTime, e.Duration})
+		}
 
-			r.totalDuration *= time.Second
-			r.taskDuration *= time.Second
-			r.projectDuration *= time.Second
+		// Subtasks are shown under their parents, which include the time
+		// spent on their subtasks
+		nodes := getTaskTree(times)
+		for _, n := range nodes {
+			if n.Duration > maxTask {
+				maxTask = n.Duration
+			}
+		}
 
-			totalDuration += r.taskDuration
+		chart := c.Bool("chart")
+		chartWidth := getChartWidth(90)
 
-			if r.projectName != prevProject {
-				hours := r.projectDuration.Hours()
+		for _, n := range nodes {
+			if n.Task.Project.Name != prevProject {
+				projectDuration := getProjectDuration(entries, n.Task.Project)
+				hours := projectDuration.Hours()
 				if prevProject != "" {
 					fmt.Println()
 				}
-				color.Printf(view.ProjectHours, r.projectName, hours)
-				prevProject = r.projectName
+				if clientId := n.Task.Project.ClientId; clientId != prevClient {
+					prevClient = clientId
+					if clientId != 0 {
+						clientDuration := clientDurations[clientId]
+						if chart {
+							color.Printf(view.ClientHoursUsage, clientNames[clientId], clientDuration.Hours(), getBar(clientDuration, maxClient, chartWidth, "cyan"))
+						} else {
+							color.Printf(view.ClientHours, clientNames[clientId], clientDuration.Hours())
+						}
+					}
+				}
+				var extra []string
+				if chart {
+					extra = append(extra, getBar(projectDuration, maxProject, chartWidth, view.ProjectNameColor(n.Task.Project.Color)))
+				}
+				if project := n.Task.Project; project.Budget != 0 {
+					extra = append(extra, getUsage(projectTotals[project.Id], project.Budget))
+				}
+				if len(extra) != 0 {
+					color.Printf(view.ProjectHoursUsage, projectTag(n.Task.Project), hours, strings.Join(extra, " "))
+				} else {
+					color.Printf(view.ProjectHours, projectTag(n.Task.Project), hours)
+				}
+				prevProject = n.Task.Project.Name
 			}
 
-			color.Printf(
-				view.FrameTimesDurationTask,
-				r.startDate.Format("Mon Jan 02"),
-				r.endDate.Format("Mon Jan 02 2006"),
-				util.GetHours(r.taskDuration),
-				50,
-				r.taskName,
-			)
+			hours := util.GetHours(n.Duration)
+			if chart {
+				hours = fmt.Sprintf("%6s %s", hours, getBar(n.Duration, maxTask, chartWidth, view.TaskColor(n.Task.Color)))
+			}
 
-			if showFrames {
-				frames := model.GetProjectByName(r.projectName).GetTask(r.taskName).GetFrames()
+			if task := n.Task; task.Estimate != 0 {
+				color.Printf(
+					view.FrameTimesDurationTaskUsage,
+					n.StartTime.Format("Mon Jan 02"),
+					n.EndTime.Format("Mon Jan 02 2006"),
+					hours,
+					view.TaskColor(n.Task.Color),
+					50,
+					n.Name(),
+					getUsage(taskTotals[task.Id], task.Estimate),
+				)
+			} else {
+				color.Printf(
+					view.FrameTimesDurationTask,
+					n.StartTime.Format("Mon Jan 02"),
+					n.EndTime.Format("Mon Jan 02 2006"),
+					hours,
+					view.TaskColor(n.Task.Color),
+					50,
+					n.Name(),
+				)
+			}
+
+			// Parents without frames of their own in the range only show
+			// the time spent on their subtasks
+			if showFrames && n.Index != -1 {
+				e := entries[n.Index]
+				frames := e.Task.GetFrames()
 
 				for i, frame := range frames {
 					// Don't print frames that fall outside of the --from/--to flags
-					if frame.StartTime.Before(from) || frame.EndTime.After(to) {
+					if !frame.StartTime.Before(rangeEnd) || !frame.EndTime.After(rangeStart) {
 						continue
 					}
 
@@ -204,12 +231,60 @@ var Log = &cli.Command{
 						frame.EndTime.Format("15:04"),
 						util.GetHours(frame.EndTime.Sub(frame.StartTime)),
 					)
+					frameCommits := getFrameCommits(commits, frame)
+					if saveNotes && frame.Note == "" && len(frameCommits) != 0 {
+						var subjects []string
+						for _, commit := range frameCommits {
+							subjects = append(subjects, commit.Subject)
+						}
+						frame.Note = strings.Join(subjects, "; ")
+						frame.Update()
+						savedNotes++
+					}
+					if frame.Note != "" {
+						color.Printf(view.FrameNote, frame.Note)
+					}
+					for _, commit := range frameCommits {
+						color.Printf(view.FrameCommit, commit.Hash, commit.Subject)
+					}
 				}
 				fmt.Println()
 			}
 		}
 		fmt.Println()
 		fmt.Printf(view.TotalHours, totalDuration.Hours())
+		if saveNotes {
+			s := "s"
+			if savedNotes == 1 {
+				s = ""
+			}
+			color.Printf(view.SavedFrameNotes, savedNotes, s)
+		}
 		return nil
 	},
 }
+
+// The time spent on the project in the log, which is the same for each of
+// its entries
+func getProjectDuration(entries []*model.LogEntry, project *model.Project) time.Duration {
+	for _, e := range entries {
+		if e.Task.Project.Id == project.Id {
+			return e.ProjectDuration
+		}
+	}
+	return 0
+}
+
+// The commits which were authored while the frame was running
+func getFrameCommits(commits []*git.Commit, f *model.Frame) (frameCommits []*git.Commit) {
+	end := f.EndTime
+	if f.IsRunning() {
+		end = time.Now()
+	}
+	for _, c := range commits {
+		if !c.Time.Before(f.StartTime) && c.Time.Before(end) {
+			frameCommits = append(frameCommits, c)
+		}
+	}
+	return
+}
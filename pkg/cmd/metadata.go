@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+// Flags for setting the metadata of a project or task
+func metadataFlags(of string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "desc",
+			Usage: "Set the description of the " + of,
+		},
+		&cli.BoolFlag{
+			Name:  "no-desc",
+			Usage: "Remove the description of the " + of,
+		},
+		&cli.StringFlag{
+			Name:  "color",
+			Usage: "Set the color the " + of + " is shown in (" + strings.Join(view.ProjectColors, ", ") + ")",
+		},
+		&cli.BoolFlag{
+			Name:  "no-color",
+			Usage: "Show the " + of + " in the default color",
+		},
+		&cli.StringFlag{
+			Name:  "ref",
+			Usage: "Set an external reference for the " + of + ", such as an issue key or purchase order number",
+		},
+		&cli.BoolFlag{
+			Name:  "no-ref",
+			Usage: "Remove the external reference for the " + of,
+		},
+	}
+}
+
+// Check the --color flag is a color which can be used, printing an error if
+// it isn't
+func checkColorFlag(c *cli.Context) bool {
+	if v := c.String("color"); v != "" && !view.IsColor(v) {
+		color.Printf(view.BadColor, v, strings.Join(view.ProjectColors, ", "))
+		return false
+	}
+	return true
+}
+
+// Set the metadata from the flags added by metadataFlags. The name is shown in
+// the messages, and is read after the color is changed so it's shown in the
+// new color.
+func setMetadataFlags(c *cli.Context, m model.Metadata, name func() string) {
+	if v := c.String("desc"); v != "" {
+		m.SetDescription(v)
+		color.Printf(view.DescriptionSetFor, name(), v)
+	}
+
+	if c.Bool("no-desc") {
+		m.SetDescription("")
+		color.Printf(view.DescriptionRemovedFor, name())
+	}
+
+	if v := c.String("color"); v != "" {
+		m.SetColor(v)
+		color.Printf(view.ColorSetFor, name())
+	}
+
+	if c.Bool("no-color") {
+		m.SetColor("")
+		color.Printf(view.ColorRemovedFor, name())
+	}
+
+	if v := c.String("ref"); v != "" {
+		m.SetRef(v)
+		color.Printf(view.RefSetFor, name(), v)
+	}
+
+	if c.Bool("no-ref") {
+		m.SetRef("")
+		color.Printf(view.RefRemovedFor, name())
+	}
+}
+
+// The name of the project in its color, for messages
+func projectTag(p *model.Project) string {
+	return view.ProjectName(p.Name, p.Color)
+}
+
+// The name of the task in its color, for messages
+func taskTag(t *model.Task) string {
+	return view.TaskName(t.Name, t.Color)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/server"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+var Metrics = &cli.Command{
+	Name:  "metrics",
+	Usage: "Serve Prometheus metrics on /metrics (also served by the serve command)",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "listen",
+			Aliases: []string{"l"},
+			Usage:   "Address to listen on",
+			Value:   "127.0.0.1:9787",
+		},
+		&cli.StringFlag{
+			Name:    "token",
+			Usage:   "Require requests to send the token in an \"Authorization: Bearer\" header",
+			EnvVars: []string{"TRACK_TOKEN"},
+		},
+	},
+	Action: func(c *cli.Context) error {
+		db.Fatal = server.DbFatal
+		s := server.NewMetrics(c.String("token"))
+		color.Printf(view.ServerListening, c.String("listen")+"/metrics")
+		listenAndServe(c.String("listen"), s)
+		return nil
+	},
+}
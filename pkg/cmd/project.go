@@ -1,18 +1,24 @@
 package cmd
 
 import (
+	"log"
+	"strings"
+	"time"
+
 	"github.com/gookit/color"
 	"github.com/jasonwoodland/track/pkg/completion"
-	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/hooks"
 	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/presenter"
+	"github.com/jasonwoodland/track/pkg/util"
 	"github.com/jasonwoodland/track/pkg/view"
 	"github.com/urfave/cli/v2"
 )
 
 var ProjectCmds = &cli.Command{
-	Name:  "project",
-	Usage: "Manage projects",
+	Name:         "project",
+	Usage:        "Manage projects",
+	BashComplete: completion.CommandCompletion,
 	Subcommands: []*cli.Command{
 		{
 			Name:      "add",
@@ -28,7 +34,7 @@ var ProjectCmds = &cli.Command{
 					color.Printf(view.ProjectAlreadyExists, name)
 					return nil
 				}
-				db.Db.Exec("insert into project (name) values ($1)", name)
+				model.AddProject(name)
 				color.Printf(view.AddedProject, name)
 				return nil
 			},
@@ -45,12 +51,17 @@ var ProjectCmds = &cli.Command{
 					cli.ShowSubcommandHelp(c)
 					return nil
 				}
-				if model.GetProjectByName(oldName) == nil {
-					color.Printf(view.ProjectDoesNotExist, oldName)
+				project := findProject(oldName)
+				if project == nil {
+					return nil
+				}
+				if model.GetProjectByName(newName) != nil {
+					color.Printf(view.ProjectAlreadyExists, newName)
 					return nil
 				}
-				db.Db.Exec("update project set name = $1 where name = $2", newName, oldName)
-				color.Printf(view.RenamedProject, oldName, newName)
+				oldName = project.Name
+				project.Rename(newName)
+				color.Printf(view.RenamedProject, view.ProjectName(oldName, project.Color), projectTag(project))
 				return nil
 			},
 		},
@@ -67,17 +78,162 @@ var ProjectCmds = &cli.Command{
 					return nil
 				}
 
-				if model.GetProjectByName(name) == nil {
-					color.Printf(view.ProjectDoesNotExist, name)
+				project := findExactProject(name)
+				if project == nil {
+					return nil
+				}
+
+				if !presenter.Confirm(color.Sprintf(view.ConfirmDeleteProject, projectTag(project)), false) {
+					return nil
+				}
+
+				var frames []*model.Frame
+				for _, t := range project.GetTasks() {
+					frames = append(frames, t.GetFrames()...)
+				}
+				project.Remove()
+				runPostEditHooks(hooks.ActionRemove, frames)
+				color.Printf(view.DeletedProject, projectTag(project))
+				return nil
+			},
+		},
+		{
+			Name:         "archive",
+			Usage:        "Hide a project from the list of projects, completion and start, keeping its frames",
+			ArgsUsage:    "name",
+			BashComplete: completion.ProjectCompletion,
+			Action: func(c *cli.Context) error {
+				name := c.Args().Get(0)
+				if name == "" {
+					cli.ShowSubcommandHelp(c)
 					return nil
 				}
 
-				if !presenter.Confirm(color.Sprintf(view.ConfirmDeleteProject, name), false) {
+				project := findProject(name)
+				if project == nil {
 					return nil
 				}
 
-				db.Db.Exec("delete from project where name = $1", name)
-				color.Printf(view.DeletedProject, name)
+				project.SetArchived(true)
+				color.Printf(view.ArchivedProject, projectTag(project))
+				return nil
+			},
+		},
+		{
+			Name:         "unarchive",
+			Usage:        "Restore an archived project",
+			ArgsUsage:    "name",
+			BashComplete: completion.ArchivedProjectCompletion,
+			Action: func(c *cli.Context) error {
+				name := c.Args().Get(0)
+				if name == "" {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				project := findProject(name)
+				if project == nil {
+					return nil
+				}
+
+				project.SetArchived(false)
+				color.Printf(view.UnarchivedProject, projectTag(project))
+				return nil
+			},
+		},
+		{
+			Name:         "set",
+			Usage:        "Set an option for a project",
+			ArgsUsage:    "name",
+			BashComplete: completion.ProjectCompletion,
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:    "budget",
+					Aliases: []string{"b"},
+					Usage:   "Set the time budget for the project (eg. --budget 120h)",
+				},
+				&cli.BoolFlag{
+					Name:    "no-budget",
+					Aliases: []string{"B"},
+					Usage:   "Remove the time budget for the project",
+				},
+				&cli.StringFlag{
+					Name:    "dir",
+					Aliases: []string{"d"},
+					Usage:   "Use the project for tasks started in a directory and its subdirectories (eg. --dir ~/code/acme)",
+				},
+				&cli.BoolFlag{
+					Name:    "no-dir",
+					Aliases: []string{"D"},
+					Usage:   "Remove the directory for the project",
+				},
+				&cli.StringFlag{
+					Name:    "client",
+					Aliases: []string{"c"},
+					Usage:   "Set the client the project is billed to",
+				},
+				&cli.BoolFlag{
+					Name:    "no-client",
+					Aliases: []string{"C"},
+					Usage:   "Remove the client for the project",
+				},
+			}, metadataFlags("project")...),
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 1 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				name := c.Args().Get(0)
+
+				project := findProject(name)
+				if project == nil || !checkColorFlag(c) {
+					return nil
+				}
+
+				if v := c.String("budget"); v != "" {
+					budget, err := time.ParseDuration(v)
+					if err != nil {
+						log.Fatalf("Bad duration: %s", v)
+					}
+					project.SetBudget(budget)
+					color.Printf(view.BudgetSetForProject, projectTag(project), util.GetHours(budget))
+				}
+
+				if c.Bool("no-budget") {
+					project.SetBudget(0)
+					color.Printf(view.BudgetRemovedForProject, projectTag(project))
+				}
+
+				if v := c.String("dir"); v != "" {
+					dir, err := project.SetDir(v)
+					if err != nil {
+						log.Fatal(err)
+					}
+					color.Printf(view.DirSetForProject, projectTag(project), dir)
+				}
+
+				if c.Bool("no-dir") {
+					project.SetDir("")
+					color.Printf(view.DirRemovedForProject, projectTag(project))
+				}
+
+				if v := c.String("client"); v != "" {
+					client := findClient(v)
+					if client == nil {
+						return nil
+					}
+					project.SetClient(client)
+					color.Printf(view.ClientSetForProject, projectTag(project), client.Name)
+				}
+
+				if c.Bool("no-client") {
+					project.SetClient(nil)
+					color.Printf(view.ClientRemovedForProject, projectTag(project))
+				}
+
+				setMetadataFlags(c, project, func() string { return projectTag(project) })
+
 				return nil
 			},
 		},
@@ -87,9 +243,33 @@ var ProjectCmds = &cli.Command{
 var Projects = &cli.Command{
 	Name:  "projects",
 	Usage: "List projects",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "all",
+			Aliases: []string{"a"},
+			Usage:   "Include archived projects",
+		},
+	},
 	Action: func(c *cli.Context) error {
 		for _, project := range model.GetProjects() {
-			color.Magenta.Println(project.Name)
+			// The description and reference are shown after the name
+			var extra []string
+			if project.Description != "" {
+				extra = append(extra, project.Description)
+			}
+			if project.Ref != "" {
+				extra = append(extra, "("+project.Ref+")")
+			}
+
+			switch {
+			case project.Archived && c.Bool("all"):
+				color.Printf(view.ArchivedProjectName, projectTag(project))
+			case project.Archived:
+			case len(extra) != 0:
+				color.Printf(view.ProjectExtra, projectTag(project), strings.Join(extra, " "))
+			default:
+				color.Println(projectTag(project))
+			}
 		}
 		return nil
 	},
@@ -5,11 +5,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gookit/color"
-	"github.com/jasonwoodland/track/pkg/db"
-	"github.com/jasonwoodland/track/pkg/mytime"
+	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/util"
 	"github.com/jasonwoodland/track/pkg/view"
 	"github.com/urfave/cli/v2"
@@ -29,161 +30,286 @@ var Report = &cli.Command{
 			Aliases: []string{"m"},
 			Usage:   "Output monthly tracked hours",
 		},
+		&cli.BoolFlag{
+			Name:  "over-budget",
+			Usage: "Only include tasks over their estimate or projects over their budget",
+		},
+		&cli.BoolFlag{
+			Name:  "chart",
+			Usage: "Show a bar chart of the time spent on each project and task",
+		},
+		clientFlag,
+		timezoneFlag,
 	},
+	Before: setTimezone,
 	Action: func(c *cli.Context) error {
 		var (
-			fromDate = util.MonthFromShorthand(c.Args().Get(0))
-			toDate   = time.Date(fromDate.Year(), fromDate.Month()+1, 1, 0, 0, 0, 0, time.UTC)
-			query    string
-			params   []interface{}
-			monthly  = c.Bool("monthly")
+			month    = util.MonthFromShorthand(c.Args().Get(0))
+			fromDate = time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+			toDate   = time.Date(month.Year(), month.Month()+1, 1, 0, 0, 0, 0, time.Local)
+			entries  []*model.ReportEntry
 		)
 
-		query = `
-			select
-				p.name,
-				t.name,
-				iif(
-					t.monthly or ?,
-					(select min(start_time) from frame where task_id = t.id and end_time > ?),
-					min(f.start_time)
-				) start_time,
-				iif(
-					t.monthly or ?,
-				    (select max(end_time) from frame where task_id = t.id and end_time < ?),
-					max(f.end_time)
-				) end_time,
-				iif(
-					t.monthly or ?,
-					sum(case when start_time > ? and end_time < ? then strftime("%s", end_time) - strftime("%s", start_time) else 0 end),
-					sum(strftime("%s", end_time) - strftime("%s", start_time))
-				) total,
-				(t.monthly or ?) monthly
-			from task t
-			left join frame f on f.task_id = t.id
-			left join project p on p.id = t.project_id
-			group by t.id
-			having
-				(end_time > ? and end_time < ?) or ((monthly or ?) = true and total > 0)
-			order by p.name, start_time;
-		`
-
-		params = []interface{}{
-			monthly,
-			fromDate.Format(time.RFC3339),
-			monthly,
-			toDate.Format(time.RFC3339),
-			monthly,
-			fromDate.Format(time.RFC3339),
-			toDate.Format(time.RFC3339),
-			monthly,
-			fromDate.Format(time.RFC3339),
-			toDate.Format(time.RFC3339),
-			monthly,
+		client, ok := getClientFlag(c)
+		if !ok {
+			return nil
 		}
 
-		rows, err := db.Db.Query(query, params...)
-		if err != nil {
-			log.Fatal(err)
-		}
+		// The totals for usage of estimates and budgets are found at once
+		// rather than for each task and project
+		taskTotals := model.GetTaskTotals()
+		projectTotals := model.GetProjectTotals()
+
+		for _, e := range model.GetReport(fromDate, toDate, c.Bool("monthly")) {
+			if client != nil && e.Task.Project.ClientId != client.Id {
+				continue
+			}
 
-		type row struct {
-			projectName  string
-			taskName     string
-			startDate    time.Time
-			endDate      time.Time
-			taskDuration time.Duration
-			monthly      bool
+			// Only include tasks over their estimate, or tasks on projects
+			// over their budget
+			if c.Bool("over-budget") {
+				taskOver := e.Task.Estimate != 0 && taskTotals[e.Task.Id] > e.Task.Estimate
+				projectOver := e.Task.Project.Budget != 0 && projectTotals[e.Task.Project.Id] > e.Task.Project.Budget
+				if !taskOver && !projectOver {
+					continue
+				}
+			}
+
+			entries = append(entries, e)
 		}
 
+		// Projects are grouped by client, after the projects without one
+		clientNames := getClientNames()
+		sort.SliceStable(entries, func(i, j int) bool {
+			return clientNames[entries[i].Task.Project.ClientId] < clientNames[entries[j].Task.Project.ClientId]
+		})
+
 		if c.Bool("csv") {
 			w := csv.NewWriter(os.Stdout)
 
-			w.Write([]string{
+			// The client column and subtotals are only added when projects
+			// have clients, so the columns don't change for those who don't
+			// use them
+			withClients := false
+			for _, e := range entries {
+				if e.Task.Project.ClientId != 0 {
+					withClients = true
+				}
+			}
+
+			// Likewise the reference column is only added at the end when
+			// projects or tasks have references
+			withRefs := false
+			for _, e := range entries {
+				if e.Task.GetRef() != "" {
+					withRefs = true
+				}
+			}
+
+			header := []string{
 				"Project",
 				"Task",
 				"Start",
 				"End",
 				"Total",
-			})
-
-			numRows := 0
-
-			for rows.Next() {
-				numRows++
-				r := row{}
-				rows.Scan(
-					&r.projectName,
-					&r.taskName,
-					(*mytime.Time)(&r.startDate),
-					(*mytime.Time)(&r.endDate),
-					&r.taskDuration,
-					&r.monthly,
-				)
-				r.taskDuration *= time.Second
+				"Estimate",
+				"Used",
+			}
+			if withClients {
+				header = append([]string{"Client"}, header...)
+			}
+			if withRefs {
+				header = append(header, "Ref")
+			}
+			w.Write(header)
+
+			row := 2
+			for i, e := range entries {
+				clientId := e.Task.Project.ClientId
+				if withClients && clientId != 0 && (i == 0 || clientId != entries[i-1].Task.Project.ClientId) {
+					n := 0
+					for _, next := range entries[i:] {
+						if next.Task.Project.ClientId != clientId {
+							break
+						}
+						n++
+					}
+					subtotal := []string{
+						clientNames[clientId],
+						"Subtotal",
+						"",
+						"",
+						"",
+						fmt.Sprintf("=SUBTOTAL(9,F%d:F%d)", row+1, row+n),
+						"",
+						"",
+					}
+					if withRefs {
+						subtotal = append(subtotal, "")
+					}
+					w.Write(subtotal)
+					row++
+				}
 
 				marker := ""
-				if r.monthly {
+				if e.Monthly {
 					marker = "*"
 				}
 
-				if err := w.Write([]string{
-					r.projectName,
-					r.taskName + marker,
-					r.startDate.Format("Mon Jan 02 2006"),
-					r.endDate.Format("Mon Jan 02 2006"),
-					fmt.Sprintf("%.2f", r.taskDuration.Hours()),
-				}); err != nil {
+				estimate, used := "", ""
+				if e.Task.Estimate != 0 {
+					estimate = fmt.Sprintf("%.2f", e.Task.Estimate.Hours())
+					used = fmt.Sprintf("%.0f%%", util.GetPercent(taskTotals[e.Task.Id], e.Task.Estimate))
+				}
+
+				record := []string{
+					e.Task.Project.Name,
+					e.Task.Name + marker,
+					e.StartTime.Format("Mon Jan 02 2006"),
+					e.EndTime.Format("Mon Jan 02 2006"),
+					fmt.Sprintf("%.2f", e.Duration.Hours()),
+					estimate,
+					used,
+				}
+				if withClients {
+					record = append([]string{clientNames[clientId]}, record...)
+				}
+				if withRefs {
+					record = append(record, e.Task.GetRef())
+				}
+				if err := w.Write(record); err != nil {
 					log.Fatalln("error outputting csv:", err)
 				}
+				row++
 			}
 
-			w.Write([]string{
-				"Total",
-				"",
-				"",
-				"",
-				fmt.Sprintf("=SUM(E2:E%d)", numRows+1),
-			})
+			var total []string
+			if withClients {
+				// SUBTOTAL ignores the client subtotals
+				total = []string{
+					"Total",
+					"",
+					"",
+					"",
+					"",
+					fmt.Sprintf("=SUBTOTAL(9,F2:F%d)", row-1),
+					"",
+					"",
+				}
+			} else {
+				total = []string{
+					"Total",
+					"",
+					"",
+					"",
+					fmt.Sprintf("=SUM(E2:E%d)", len(entries)+1),
+					"",
+					"",
+				}
+			}
+			if withRefs {
+				total = append(total, "")
+			}
+			w.Write(total)
 
 			w.Flush()
 		} else {
 			var lastProjectName string
+			var lastClientId int64
 
-			for rows.Next() {
-				r := row{}
-				rows.Scan(
-					&r.projectName,
-					&r.taskName,
-					(*mytime.Time)(&r.startDate),
-					(*mytime.Time)(&r.endDate),
-					&r.taskDuration,
-					&r.monthly,
-				)
-				r.taskDuration *= time.Second
-
-				if lastProjectName != r.projectName {
+			chart := c.Bool("chart")
+			chartWidth := getChartWidth(85)
+
+			var maxTask, maxProject, maxClient time.Duration
+			var times []taskTime
+			projectDurations := make(map[string]time.Duration)
+			clientDurations := make(map[int64]time.Duration)
+			for _, e := range entries {
+				projectDurations[e.Task.Project.Name] += e.Duration
+				if projectDurations[e.Task.Project.Name] > maxProject {
+					maxProject = projectDurations[e.Task.Project.Name]
+				}
+				clientDurations[e.Task.Project.ClientId] += e.Duration
+				if clientDurations[e.Task.Project.ClientId] > maxClient {
+					maxClient = clientDurations[e.Task.Project.ClientId]
+				}
+				times = append(times, taskTime{e.Task, e.StartTime, e.EndTime, e.Duration})
+			}
+
+			// Subtasks are shown under their parents, which include the time
+			// spent on their subtasks
+			nodes := getTaskTree(times)
+			for _, n := range nodes {
+				if n.Duration > maxTask {
+					maxTask = n.Duration
+				}
+			}
+
+			for _, n := range nodes {
+				if lastProjectName != n.Task.Project.Name {
 					if lastProjectName != "" {
 						color.Println()
 					}
-					color.Printf(view.Project, r.projectName)
+					if clientId := n.Task.Project.ClientId; clientId != lastClientId {
+						lastClientId = clientId
+						if clientId != 0 {
+							clientDuration := clientDurations[clientId]
+							if chart {
+								color.Printf(view.ClientHoursUsage, clientNames[clientId], clientDuration.Hours(), getBar(clientDuration, maxClient, chartWidth, "cyan"))
+							} else {
+								color.Printf(view.ClientHours, clientNames[clientId], clientDuration.Hours())
+							}
+						}
+					}
+					var extra []string
+					if chart {
+						extra = append(extra, util.GetHours(projectDurations[n.Task.Project.Name]))
+						extra = append(extra, getBar(projectDurations[n.Task.Project.Name], maxProject, chartWidth, view.ProjectNameColor(n.Task.Project.Color)))
+					}
+					if project := n.Task.Project; project.Budget != 0 {
+						extra = append(extra, getUsage(projectTotals[project.Id], project.Budget))
+					}
+					if len(extra) != 0 {
+						color.Printf(view.ProjectUsage, projectTag(n.Task.Project), strings.Join(extra, " "))
+					} else {
+						color.Printf(view.Project, projectTag(n.Task.Project))
+					}
 				}
 
 				marker := ""
-				if r.monthly {
+				if n.Index != -1 && entries[n.Index].Monthly {
 					marker = "*"
 				}
 
-				color.Printf(
-					view.FrameTimesDurationTask,
-					r.startDate.Format("Mon Jan 02"),
-					r.endDate.Format("Mon Jan 02"),
-					util.GetHours(r.taskDuration),
-					50,
-					r.taskName+marker,
-				)
+				hours := util.GetHours(n.Duration)
+				if chart {
+					hours = fmt.Sprintf("%6s %s", hours, getBar(n.Duration, maxTask, chartWidth, view.TaskColor(n.Task.Color)))
+				}
+
+				if n.Task.Estimate != 0 {
+					color.Printf(
+						view.FrameTimesDurationTaskUsage,
+						n.StartTime.Format("Mon Jan 02"),
+						n.EndTime.Format("Mon Jan 02"),
+						hours,
+						view.TaskColor(n.Task.Color),
+						50,
+						n.Name()+marker,
+						getUsage(taskTotals[n.Task.Id], n.Task.Estimate),
+					)
+				} else {
+					color.Printf(
+						view.FrameTimesDurationTask,
+						n.StartTime.Format("Mon Jan 02"),
+						n.EndTime.Format("Mon Jan 02"),
+						hours,
+						view.TaskColor(n.Task.Color),
+						50,
+						n.Name()+marker,
+					)
+				}
 
-				lastProjectName = r.projectName
+				lastProjectName = n.Task.Project.Name
 			}
 
 			color.Println()
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/cleanup"
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/server"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+var Serve = &cli.Command{
+	Name:  "serve",
+	Usage: "Serve a JSON API for reading and controlling the timer",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "listen",
+			Aliases: []string{"l"},
+			Usage:   "Address to listen on",
+			Value:   "127.0.0.1:8787",
+		},
+		&cli.StringFlag{
+			Name:    "token",
+			Usage:   "Require requests to send the token in an \"Authorization: Bearer\" header",
+			EnvVars: []string{"TRACK_TOKEN"},
+		},
+	},
+	Action: func(c *cli.Context) error {
+		db.Fatal = server.DbFatal
+		s := server.New(c.String("token"))
+		go s.DeliverWebhooks()
+		color.Printf(view.ServerListening, c.String("listen"))
+		listenAndServe(c.String("listen"), s)
+		return nil
+	},
+}
+
+// Serve requests until interrupted, then wait for the requests being handled
+// to finish
+func listenAndServe(addr string, handler http.Handler) {
+	srv := &http.Server{Addr: addr, Handler: handler}
+	cleanup.SetCleanupFn(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	})
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
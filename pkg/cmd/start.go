@@ -2,13 +2,11 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/gookit/color"
-	"github.com/jasonwoodland/track/pkg/cleanup"
 	"github.com/jasonwoodland/track/pkg/completion"
-	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/hooks"
 	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/presenter"
 	"github.com/jasonwoodland/track/pkg/util"
@@ -19,7 +17,7 @@ import (
 var Start = &cli.Command{
 	Name:      "start",
 	Usage:     "Start tracking time for a task",
-	ArgsUsage: "project task",
+	ArgsUsage: "[project] task",
 	Flags: []cli.Flag{
 		&cli.StringFlag{
 			Name:  "ago",
@@ -29,6 +27,14 @@ var Start = &cli.Command{
 			Name:  "in",
 			Usage: "Start tracking in a given duration (eg. --in 5m)",
 		},
+		&cli.BoolFlag{
+			Name:  "new",
+			Usage: "Add a new task with the exact name given, even if it is similar to an existing task",
+		},
+		&cli.BoolFlag{
+			Name:  "git",
+			Usage: "Use the git repository as the project and the branch as the task",
+		},
 		&cli.BoolFlag{
 			Name:    "watch",
 			Aliases: []string{"w"},
@@ -41,25 +47,52 @@ var Start = &cli.Command{
 
 		projectName := c.Args().Get(0)
 		taskName := c.Args().Get(1)
+		if c.Bool("git") && c.Args().Len() == 0 {
+			var err error
+			if projectName, taskName, err = gitProjectTask("."); err != nil {
+				color.Printf(view.GitError, err)
+				return nil
+			}
+		} else if c.Args().Len() == 1 {
+			// Only the task was given, so use the project for the directory
+			taskName = projectName
+			if projectName = model.GetProjectNameForDir("."); projectName == "" {
+				color.Printf(view.NoProjectForDir)
+				return nil
+			}
+		}
 		if projectName == "" || taskName == "" {
 			cli.ShowSubcommandHelp(c)
 			return nil
 		}
 
-		project := model.GetProjectByName(projectName)
+		project := findActiveProject(projectName)
 		if project == nil {
-			color.Printf(view.ProjectDoesNotExist, projectName)
 			return nil
 		}
 
-		task := project.GetTask(taskName)
+		task, ok := findTaskOrNew(project, taskName, c.Bool("new"))
+		if !ok {
+			return nil
+		}
+
+		now := startTime
+
+		ago, err := time.ParseDuration(c.String("ago"))
+		if err == nil {
+			startTime = startTime.Add(0 - ago)
+		}
+
+		if in, err := time.ParseDuration(c.String("in")); err == nil {
+			startTime = startTime.Add(in)
+		}
 
 		state := model.GetState()
 		if state != nil && state.Running {
 			color.Printf(
 				view.AlreadyRunningProjectTaskElapsedTotal,
-				state.Task.Project.Name,
-				state.Task.Name,
+				projectTag(state.Task.Project),
+				taskTag(&state.Task),
 				util.GetHours(state.TimeElapsed),
 				util.GetHours(state.Task.GetTotal()),
 			)
@@ -71,24 +104,30 @@ var Start = &cli.Command{
 			if task != nil && state.Task.Id == task.Id {
 				return nil
 			}
+		}
+
+		// The task may not exist yet, so give the hook what it would be called
+		hookTask := task
+		if hookTask == nil {
+			hookTask = &model.Task{Project: project, Name: taskName}
+		}
+		if !runPreHook(hooks.PreStart, hooks.ActionStart, &model.Frame{Task: hookTask, StartTime: startTime}) {
+			return nil
+		}
+
+		if state != nil && state.Running {
 			if presenter.Confirm(view.ConfirmStopRunningTask, true) {
-				_, err := db.Db.Exec(
-					"update frame set end_time = $1 where end_time is null",
-					startTime.Format(time.RFC3339),
-				)
-				if err != nil {
-					log.Fatal(err)
-				}
+				stopFrame(now)
 				color.Printf(
 					view.StoppedProjectTaskElapsedTotal,
-					state.Task.Project.Name,
-					state.Task.Name,
+					projectTag(state.Task.Project),
+					taskTag(&state.Task),
 					util.GetHours(state.TimeElapsed),
 					util.GetHours(state.Task.GetTotal()),
 				)
 				color.Printf(
 					view.FinishedAtTimeElapsed,
-					startTime.Format("15:04"),
+					now.Format("15:04"),
 					state.TimeElapsed.Round(time.Second),
 				)
 
@@ -97,25 +136,7 @@ var Start = &cli.Command{
 			}
 		}
 
-		if task == nil {
-			color.Printf(view.AddedTask, taskName)
-			task = project.AddTask(taskName)
-		}
-
-		ago, err := time.ParseDuration(c.String("ago"))
-		if err == nil {
-			startTime = startTime.Add(0 - ago)
-		}
-
-		if in, err := time.ParseDuration(c.String("in")); err == nil {
-			startTime = startTime.Add(in)
-		}
-
-		db.Db.Exec(
-			"insert into frame (task_id, start_time) values ($1, $2)",
-			task.Id,
-			startTime.Format(time.RFC3339),
-		)
+		task = startFrame(project, task, taskName, startTime)
 
 		if c.Bool("watch") {
 			printStatus := func() {
@@ -126,8 +147,8 @@ var Start = &cli.Command{
 				}
 				color.Printf(
 					view.RunningProjectTaskElapsedTotal,
-					state.Task.Project.Name,
-					state.Task.Name,
+					projectTag(state.Task.Project),
+					taskTag(&state.Task),
 					util.GetHours(state.TimeElapsed),
 					util.GetHours(state.Task.GetTotal()),
 				)
@@ -136,38 +157,31 @@ var Start = &cli.Command{
 					state.StartTime.Format("15:04"),
 					state.TimeElapsed.Round(time.Second),
 				)
+				printEstimateWarnings(&state.Task)
 			}
 
-			cleanup.SetCleanupFn(func() {
-				printStatus()
-			})
-
-			fmt.Printf("\033[?1049h\033[H")
-			for {
-				printStatus()
-				time.Sleep(time.Second)
-				fmt.Printf("\033[H")
-			}
+			watch(printStatus, time.Second)
 		} else {
 			if ago != 0 {
 				state := model.GetState()
 				color.Printf(
 					view.RunningProjectTaskElapsedTotal,
-					project.Name,
-					task.Name,
+					projectTag(project),
+					taskTag(task),
 					util.GetHours(state.TimeElapsed),
 					util.GetHours(state.Task.GetTotal()),
 				)
 			} else {
 				color.Printf(
 					view.RunningProjectTaskTotal,
-					project.Name,
-					task.Name,
+					projectTag(project),
+					taskTag(task),
 					util.GetHours(task.GetTotal()),
 				)
 			}
 
 			color.Printf(view.StartedAtTime, startTime.Format("15:04"))
+			printEstimateWarnings(task)
 		}
 
 		return nil
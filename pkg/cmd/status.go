@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"log"
+	"os"
+	"text/template"
 	"time"
 
 	"github.com/gookit/color"
@@ -12,6 +16,74 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+// Preset formats for status bars, which output nothing (or an empty block)
+// when not running
+var statusFormats = map[string]string{
+	"tmux":        `{{if .Running}}#[fg=magenta]{{.Project}}#[default] #[fg=blue]{{.Task}}#[default] {{.Hours}}{{end}}`,
+	"polybar":     `{{if .Running}}%{F#d33682}{{.Project}}%{F-} %{F#268bd2}{{.Task}}%{F-} {{.Hours}}{{end}}`,
+	"waybar-json": `{"text": {{json .Text}}, "tooltip": {{json .Tooltip}}, "class": {{if .Running}}"running"{{else}}"stopped"{{end}}}`,
+	"i3blocks":    "{{if .Running}}{{.Text}}\n{{.Task}} {{.Hours}}\n#268bd2{{end}}",
+}
+
+// Fields available to status --format templates
+type statusData struct {
+	Running   bool
+	Project   string
+	Task      string
+	StartTime time.Time
+	Elapsed   time.Duration
+	Hours     string
+}
+
+// Text is the project, task and hours elapsed (eg. "acme review 1.50h")
+func (d statusData) Text() string {
+	if !d.Running {
+		return ""
+	}
+	return fmt.Sprintf("%s %s %s", d.Project, d.Task, d.Hours)
+}
+
+// Tooltip is the project, task and start time (eg. "acme review since 09:00")
+func (d statusData) Tooltip() string {
+	if !d.Running {
+		return view.NotRunning
+	}
+	return fmt.Sprintf("%s %s since %s", d.Project, d.Task, d.StartTime.Format("15:04"))
+}
+
+func printStatusFormat(format string) {
+	if f, ok := statusFormats[format]; ok {
+		format = f
+	}
+
+	t, err := template.New("status").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(format)
+	if err != nil {
+		log.Fatalln("bad format:", err)
+	}
+
+	data := statusData{}
+	if state := model.GetState(); state.Running {
+		data = statusData{
+			Running:   true,
+			Project:   state.Task.Project.Name,
+			Task:      state.Task.Name,
+			StartTime: state.StartTime,
+			Elapsed:   state.TimeElapsed.Round(time.Second),
+			Hours:     util.GetHours(state.TimeElapsed),
+		}
+	}
+
+	if err := t.Execute(os.Stdout, data); err != nil {
+		log.Fatalln("bad format:", err)
+	}
+	fmt.Println()
+}
+
 var Status = &cli.Command{
 	Name:  "status",
 	Usage: "Display status of running task",
@@ -24,8 +96,23 @@ var Status = &cli.Command{
 			Aliases: []string{"w"},
 			Usage:   "Output the current status to the screen periodically",
 		},
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "Output the status with a Go template (eg. '{{if .Running}}{{.Project}}/{{.Task}} {{.Elapsed}}{{end}}') or a preset (tmux, polybar, waybar-json or i3blocks)",
+		},
 	},
 	Action: func(c *cli.Context) error {
+		if format := c.String("format"); format != "" {
+			printStatusFormat(format)
+			return nil
+		}
+
+		var dirProject *model.Project
+		if name := model.GetProjectNameForDir("."); name != "" {
+			dirProject = model.GetProjectByName(name)
+		}
+
 		printStatus := func() {
 			state := model.GetState()
 			if !state.Running {
@@ -34,21 +121,20 @@ var Status = &cli.Command{
 			}
 			color.Printf(
 				view.RunningProjectTaskElapsedTotal,
-				state.Task.Project.Name,
-				state.Task.Name,
+				projectTag(state.Task.Project),
+				taskTag(&state.Task),
 				util.GetHours(state.TimeElapsed),
 				util.GetHours(state.Task.GetTotal()),
 			)
 			color.Printf(view.StartedAtTimeElapsed, state.StartTime.Format("15:04"), state.TimeElapsed.Round(time.Second))
+			printEstimateWarnings(&state.Task)
+			if dirProject != nil && dirProject.Id != state.Task.Project.Id {
+				color.Printf(view.WarnProjectNotForDir, projectTag(state.Task.Project), projectTag(dirProject))
+			}
 		}
 
 		if c.Bool("watch") {
-			fmt.Printf("\033[?1049h\033[H")
-			for {
-				printStatus()
-				time.Sleep(time.Second)
-				fmt.Printf("\033[H")
-			}
+			watch(printStatus, time.Second)
 		}
 
 		printStatus()
@@ -2,11 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/gookit/color"
-	"github.com/jasonwoodland/track/pkg/db"
 	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/util"
 	"github.com/jasonwoodland/track/pkg/view"
@@ -40,21 +38,13 @@ var Stop = &cli.Command{
 			state.TimeElapsed += in
 		}
 
-		res, err := db.Db.Exec(
-			"update frame set end_time = $1 where end_time is null",
-			endTime.Format(time.RFC3339),
-		)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		if n, _ := res.RowsAffected(); n == 0 {
+		if !stopFrame(endTime) {
 			fmt.Println("No task started")
 		} else {
 			color.Printf(
 				view.StoppedProjectTaskElapsedTotal,
-				state.Task.Project.Name,
-				state.Task.Name,
+				projectTag(state.Task.Project),
+				taskTag(&state.Task),
 				util.GetHours(state.TimeElapsed),
 				util.GetHours(state.Task.GetTotal()),
 			)
@@ -2,19 +2,22 @@ package cmd
 
 import (
 	"log"
+	"time"
 
 	"github.com/gookit/color"
 	"github.com/jasonwoodland/track/pkg/completion"
 	"github.com/jasonwoodland/track/pkg/db"
-	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/hooks"
 	"github.com/jasonwoodland/track/pkg/presenter"
+	"github.com/jasonwoodland/track/pkg/util"
 	"github.com/jasonwoodland/track/pkg/view"
 	"github.com/urfave/cli/v2"
 )
 
 var TaskCmds = &cli.Command{
-	Name:  "task",
-	Usage: "Manage tasks on a project",
+	Name:         "task",
+	Usage:        "Manage tasks on a project",
+	BashComplete: completion.CommandCompletion,
 	Subcommands: []*cli.Command{
 		{
 			Name:         "rename",
@@ -31,25 +34,27 @@ var TaskCmds = &cli.Command{
 				oldName := c.Args().Get(1)
 				newName := c.Args().Get(2)
 
-				project := model.GetProjectByName(projectName)
-
+				project := findProject(projectName)
 				if project == nil {
-					color.Printf(view.ProjectDoesNotExist, projectName)
 					return nil
 				}
 
-				if project.GetTask(newName) != nil {
-					color.Printf(view.TaskAlreadyExistsForProject, newName, projectName)
+				task := findTask(project, oldName)
+				if task == nil {
 					return nil
 				}
 
-				if project.GetTask(oldName) == nil {
-					color.Printf(view.TaskDoesNotExistForProject, oldName, projectName)
+				if path := task.RenamedPath(newName); project.GetTask(path) != nil {
+					color.Printf(view.TaskAlreadyExistsForProject, view.TaskName(path, ""), projectTag(project))
 					return nil
 				}
 
-				db.Db.Exec("update task set name = $1 where name = $2 and project_id = $3", newName, oldName, project.Id)
-				color.Printf(view.RenamedTaskOnProject, oldName, newName, projectName)
+				oldTag := taskTag(task)
+				if err := task.Rename(newName); err != nil {
+					color.Printf(view.CantRenameTask, err)
+					return nil
+				}
+				color.Printf(view.RenamedTaskOnProject, oldTag, taskTag(task), projectTag(project))
 				return nil
 			},
 		},
@@ -68,36 +73,60 @@ var TaskCmds = &cli.Command{
 				projectName := c.Args().Get(0)
 				taskName := c.Args().Get(1)
 
-				project := model.GetProjectByName(projectName)
+				project := findExactProject(projectName)
 				if project == nil {
-					color.Printf(view.ProjectDoesNotExist, projectName)
 					return nil
 				}
 
-				task := project.GetTask(taskName)
-
+				task := findExactTask(project, taskName)
 				if task == nil {
-					color.Printf(view.TaskDoesNotExistForProject, taskName, projectName)
 					return nil
 				}
 
-				numFrames := task.GetNumFrames()
+				// Subtasks are deleted with the task
+				frames := task.GetFrames()
+				subtasks := task.GetSubtasks()
+				for _, subtask := range subtasks {
+					frames = append(frames, subtask.GetFrames()...)
+				}
+
+				numFrames := len(frames)
 				s := "s"
 				if numFrames == 1 {
 					s = ""
 				}
 
-				if !presenter.Confirm(color.Sprintf(
-					view.ConfirmDeleteTaskFramesOnProject,
-					taskName,
-					numFrames,
-					s,
-					projectName,
-				), false) {
+				var confirm string
+				if len(subtasks) == 0 {
+					confirm = color.Sprintf(
+						view.ConfirmDeleteTaskFramesOnProject,
+						taskTag(task),
+						numFrames,
+						s,
+						projectTag(project),
+					)
+				} else {
+					subtasksS := "s"
+					if len(subtasks) == 1 {
+						subtasksS = ""
+					}
+					confirm = color.Sprintf(
+						view.ConfirmDeleteTaskSubtasksFramesOnProject,
+						taskTag(task),
+						len(subtasks),
+						subtasksS,
+						numFrames,
+						s,
+						projectTag(project),
+					)
+				}
+
+				if !presenter.Confirm(confirm, false) {
 					return nil
 				}
 
-				db.Db.Exec("delete from task where name = $1 and project_id = $2", taskName, project.Id)
+				task.Remove()
+				runPostEditHooks(hooks.ActionRemove, frames)
 				color.Println(view.Deleted)
 				return nil
 			},
@@ -118,29 +147,23 @@ var TaskCmds = &cli.Command{
 				toProjectName := c.Args().Get(2)
 				toTaskName := c.Args().Get(3)
 
-				fromProject := model.GetProjectByName(fromProjectName)
+				fromProject := findExactProject(fromProjectName)
 				if fromProject == nil {
-					color.Printf(view.ProjectDoesNotExist, fromProjectName)
 					return nil
 				}
 
-				fromTask := fromProject.GetTask(fromTaskName)
-
+				fromTask := findExactTask(fromProject, fromTaskName)
 				if fromTask == nil {
-					color.Printf(view.TaskDoesNotExistForProject, fromTaskName, fromProjectName)
 					return nil
 				}
 
-				toProject := model.GetProjectByName(toProjectName)
+				toProject := findExactProject(toProjectName)
 				if toProject == nil {
-					color.Printf(view.ProjectDoesNotExist, toProjectName)
 					return nil
 				}
 
-				toTask := toProject.GetTask(toTaskName)
-
+				toTask := findExactTask(toProject, toTaskName)
 				if toTask == nil {
-					color.Printf(view.TaskDoesNotExistForProject, toTaskName, toProjectName)
 					return nil
 				}
 
@@ -154,34 +177,87 @@ var TaskCmds = &cli.Command{
 					view.ConfirmMergeFramesFromToProjectTask,
 					numFrames,
 					s,
-					fromProjectName,
-					fromTaskName,
-					toProjectName,
-					toTaskName,
+					projectTag(fromProject),
+					taskTag(fromTask),
+					projectTag(toProject),
+					taskTag(toTask),
 				), false) {
 					return nil
 				}
 
-				_, err := db.Db.Exec("update frame set task_id = $1 where task_id = $2", toTask.Id, fromTask.Id)
-				if err != nil {
-					log.Fatal(err)
+				frames := fromTask.GetFrames()
+				if err := fromTask.Merge(toTask); err != nil {
+					color.Printf(view.CantMergeTask, err)
+					return nil
 				}
-				_, err = db.Db.Exec("delete from task where id = $1", fromTask.Id)
-				if err != nil {
-					log.Fatal(err)
+				for _, f := range frames {
+					f.Task = toTask
 				}
+				runPostEditHooks(hooks.ActionMove, frames)
 
 				color.Println(view.Merged)
 
 				return nil
 			},
 		},
+		{
+			Name:         "archive",
+			Usage:        "Hide a task and its subtasks from completion and start, keeping their frames",
+			ArgsUsage:    "project task",
+			BashComplete: completion.ProjectTaskCompletion,
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 2 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				project := findProject(c.Args().Get(0))
+				if project == nil {
+					return nil
+				}
+
+				task := findTask(project, c.Args().Get(1))
+				if task == nil {
+					return nil
+				}
+
+				task.SetArchived(true)
+				color.Printf(view.ArchivedTaskOnProject, taskTag(task), projectTag(project))
+				return nil
+			},
+		},
+		{
+			Name:         "unarchive",
+			Usage:        "Restore an archived task",
+			ArgsUsage:    "project task",
+			BashComplete: completion.ProjectArchivedTaskCompletion,
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 2 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				project := findProject(c.Args().Get(0))
+				if project == nil {
+					return nil
+				}
+
+				task := findTask(project, c.Args().Get(1))
+				if task == nil {
+					return nil
+				}
+
+				task.SetArchived(false)
+				color.Printf(view.UnarchivedTaskOnProject, taskTag(task), projectTag(project))
+				return nil
+			},
+		},
 		{
 			Name:         "set",
 			Usage:        "Set an option for a task",
 			ArgsUsage:    "project task",
 			BashComplete: completion.ProjectTaskCompletion,
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				&cli.BoolFlag{
 					Name:    "monthly",
 					Aliases: []string{"m"},
@@ -192,7 +268,17 @@ var TaskCmds = &cli.Command{
 					Aliases: []string{"M"},
 					Usage:   "Disable monthly reporting",
 				},
-			},
+				&cli.StringFlag{
+					Name:    "estimate",
+					Aliases: []string{"e"},
+					Usage:   "Set the estimated time for the task (eg. --estimate 8h)",
+				},
+				&cli.BoolFlag{
+					Name:    "no-estimate",
+					Aliases: []string{"E"},
+					Usage:   "Remove the estimated time for the task",
+				},
+			}, metadataFlags("task")...),
 			Action: func(c *cli.Context) error {
 				if c.Args().Len() != 2 {
 					cli.ShowSubcommandHelp(c)
@@ -202,28 +288,42 @@ var TaskCmds = &cli.Command{
 				projectName := c.Args().Get(0)
 				taskName := c.Args().Get(1)
 
-				project := model.GetProjectByName(projectName)
-
+				project := findProject(projectName)
 				if project == nil {
-					color.Printf(view.ProjectDoesNotExist, projectName)
 					return nil
 				}
 
-				if project.GetTask(taskName) == nil {
-					color.Printf(view.TaskDoesNotExistForProject, taskName, projectName)
+				task := findTask(project, taskName)
+				if task == nil || !checkColorFlag(c) {
 					return nil
 				}
 
 				if c.Bool("monthly") {
-					db.Db.Exec("update task set monthly = true where name = $1 and project_id = $2", taskName, project.Id)
+					db.Db.Exec("update task set monthly = true where id = $1", task.Id)
 					color.Println("Monthly reporting enabled")
 				}
 
 				if c.Bool("no-monthly") {
-					db.Db.Exec("update task set monthly = false where name = $1 and project_id = $2", taskName, project.Id)
+					db.Db.Exec("update task set monthly = false where id = $1", task.Id)
 					color.Println("Monthly reporting disabled")
 				}
 
+				if v := c.String("estimate"); v != "" {
+					estimate, err := time.ParseDuration(v)
+					if err != nil {
+						log.Fatalf("Bad duration: %s", v)
+					}
+					task.SetEstimate(estimate)
+					color.Printf(view.EstimateSetForTask, taskTag(task), util.GetHours(estimate))
+				}
+
+				if c.Bool("no-estimate") {
+					task.SetEstimate(0)
+					color.Printf(view.EstimateRemovedForTask, taskTag(task))
+				}
+
+				setMetadataFlags(c, task, func() string { return taskTag(task) })
+
 				return nil
 			},
 		},
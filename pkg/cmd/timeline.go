@@ -13,6 +13,7 @@ import (
 	"github.com/jasonwoodland/track/pkg/db"
 	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/jasonwoodland/track/pkg/util"
+	"github.com/jasonwoodland/track/pkg/view"
 	"github.com/urfave/cli/v2"
 )
 
@@ -23,21 +24,50 @@ var Timeline = &cli.Command{
 	BashComplete: completion.ProjectTaskCompletion,
 	Flags: []cli.Flag{
 		&cli.StringFlag{
-			Name:     "from",
-			Aliases:  []string{"f"},
-			Usage:    "Start date for the timeline",
-			Required: true,
+			Name:    "from",
+			Aliases: []string{"f"},
+			Usage:   "Start date for the timeline (required unless --hours is used)",
 		},
 		&cli.StringFlag{
 			Name:    "to",
 			Usage:   "End date for the timeline",
 			Aliases: []string{"t"},
 		},
+		&cli.BoolFlag{
+			Name:  "hours",
+			Usage: "Show the time of day spent on each project",
+		},
+		&cli.StringFlag{
+			Name:  "slot",
+			Usage: "Duration of each cell with --hours, which must divide an hour evenly",
+			Value: "15m",
+		},
+		&cli.StringFlag{
+			Name:  "day-start",
+			Usage: "Time of day to start each row with --hours",
+			Value: "06:00",
+		},
+		&cli.StringFlag{
+			Name:  "day-end",
+			Usage: "Time of day to end each row with --hours",
+			Value: "22:00",
+		},
+		&cli.BoolFlag{
+			Name:    "watch",
+			Aliases: []string{"w"},
+			Usage:   "Output the timeline to the screen periodically with --hours",
+		},
+		timezoneFlag,
 	},
+	Before: setTimezone,
 	Action: func(c *cli.Context) error {
 		from := time.Time{}
 		if v := c.String("from"); v != "" {
 			from = util.TimeFromShorthand(v)
+		} else if c.Bool("hours") {
+			from = time.Now()
+		} else {
+			log.Fatalln("from flag required")
 		}
 
 		to := time.Now()
@@ -45,31 +75,57 @@ var Timeline = &cli.Command{
 			to = util.TimeFromShorthand(v)
 		}
 
-		to = to.Add(-24 * time.Hour)
+		if c.Bool("hours") {
+			slot, err := time.ParseDuration(c.String("slot"))
+			if err != nil || slot <= 0 {
+				log.Fatalf("Bad duration: %s", c.String("slot"))
+			}
+			// Columns are labelled by hour, so each hour needs a whole
+			// number of slots
+			if slot > time.Hour || time.Hour%slot != 0 {
+				log.Fatalf("Bad slot: %s (must divide an hour evenly)", c.String("slot"))
+			}
+			dayStart := timeOfDayFromFlag(c.String("day-start"))
+			dayEnd := timeOfDayFromFlag(c.String("day-end"))
+			if dayEnd <= dayStart {
+				dayEnd += 24 * time.Hour
+			}
 
+			printTimeline := func() {
+				printHourTimeline(from, to, c.Args().Get(0), c.Args().Get(1), slot, dayStart, dayEnd)
+			}
+			if c.Bool("watch") {
+				watch(printTimeline, 5*time.Second)
+			}
+			printTimeline()
+			return nil
+		}
+
+		// Frames spanning midnight are included on each day they cover
 		query := `
 			with recursive date(d) as (
-				select datetime(?)
+				select date(?)
 				union all
-				select datetime(d, '+1 day') from date where d < ?
+				select date(d, '+1 day') from date where d < ?
 			)
 			select
 				d,
 				p.id,
 				p.name,
 				t.id,
-				t.name
+				tp.path
 			from date
-			left join frame f on f.start_time > date.d and f.end_time < datetime(d, '+1 day')
+			left join frame f on ` + db.Overlaps("f", db.LocalDayStart("d"), db.LocalDayEnd("d")) + `
 			left join task t on t.id = f.task_id
+			left join task_path tp on tp.id = t.id
 			left join project p on p.id = t.project_id
 		`
 
 		var params []interface{}
 		var whereConds []string
 
-		params = append(params, from.Format(time.RFC3339))
-		params = append(params, to.Format(time.RFC3339))
+		params = append(params, from.Format("2006-01-02"))
+		params = append(params, to.Format("2006-01-02"))
 
 		if p := c.Args().Get(0); p != "" {
 			whereConds = append(whereConds, "(p.name like ? or p.name is null)")
@@ -77,7 +133,7 @@ var Timeline = &cli.Command{
 		}
 
 		if t := c.Args().Get(1); t != "" {
-			whereConds = append(whereConds, "(t.name like ? or t.name is null)")
+			whereConds = append(whereConds, "(tp.path like ? or tp.path is null)")
 			params = append(params, "%"+t+"%")
 		}
 
@@ -141,11 +197,16 @@ var Timeline = &cli.Command{
 		}
 		sort.Strings(dates)
 
+		absences := model.GetAbsenceDays(from, to)
+
 		fmt.Printf(strings.Repeat(" ", longest+longestProject+2))
 		for _, date := range dates {
-			d, _ := time.Parse("2006-01-02 00:00:00", date)
-			color.Printf("<gray>%3v</>", d.Day())
-
+			d, _ := time.Parse("2006-01-02", date)
+			if absences[d.Format("2006-01-02")] != nil {
+				color.Printf("<yellow>%3v</>", d.Day())
+			} else {
+				color.Printf("<gray>%3v</>", d.Day())
+			}
 		}
 		fmt.Printf("\n")
 
@@ -156,8 +217,9 @@ var Timeline = &cli.Command{
 		sort.Ints(taskIds)
 
 		for _, taskId := range taskIds {
-			color.Printf("<magenta>%-"+strconv.Itoa(longestProject)+"v</> ", model.GetTaskById(int64(taskId)).Project.Name)
-			color.Printf("<blue>%-"+strconv.Itoa(longest)+"v</> <gray>┃</>", tasks[taskId])
+			task := model.GetTaskById(int64(taskId))
+			color.Printf(view.ProjectName("%-"+strconv.Itoa(longestProject)+"v", task.Project.Color)+" ", task.Project.Name)
+			color.Printf("<%s>%-"+strconv.Itoa(longest)+"v</> <gray>┃</>", view.TaskColor(task.Color), tasks[taskId])
 			for di, date := range dates {
 				if chart[date][taskId] {
 					var prev, next bool
@@ -177,7 +239,11 @@ var Timeline = &cli.Command{
 						color.Printf("<green> ● </>")
 					}
 				} else {
-					t, _ := time.Parse("2006-01-02 00:00:00", date)
+					t, _ := time.Parse("2006-01-02", date)
+					if absences[t.Format("2006-01-02")] != nil {
+						color.Printf("<yellow> ○ </>")
+						continue
+					}
 					switch t.Weekday() {
 					case time.Saturday, time.Sunday:
 						color.Printf("<gray> ○ </>")
@@ -191,3 +257,130 @@ var Timeline = &cli.Command{
 		return nil
 	},
 }
+
+// Parse a time of day (eg. 06:00) into the duration since midnight
+func timeOfDayFromFlag(v string) time.Duration {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		log.Fatalf("Bad time: %s", v)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+// Print a row for each day, with a cell for each slot of time between
+// dayStart and dayEnd colored by the project most worked on in that slot
+func printHourTimeline(from, to time.Time, projectFilter, taskFilter string, slot, dayStart, dayEnd time.Duration) {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local)
+
+	var frames []*model.Frame
+	for _, f := range model.GetFrames(from, to.AddDate(0, 0, 2)) {
+		if strings.Contains(f.Task.Project.Name, projectFilter) && strings.Contains(f.Task.Name, taskFilter) {
+			frames = append(frames, f)
+		}
+	}
+
+	absences := model.GetAbsenceDays(from, to)
+	numSlots := int((dayEnd - dayStart) / slot)
+	slotsPerHour := int(time.Hour / slot)
+
+	// Clip the frame to a range of time, treating running frames as ending now
+	overlap := func(f *model.Frame, start, end time.Time) time.Duration {
+		frameEnd := f.EndTime
+		if frameEnd.IsZero() {
+			frameEnd = time.Now()
+		}
+		if f.StartTime.After(start) {
+			start = f.StartTime
+		}
+		if frameEnd.Before(end) {
+			end = frameEnd
+		}
+		if end.Before(start) {
+			return 0
+		}
+		return end.Sub(start)
+	}
+
+	fmt.Printf("%11s", "")
+	for i := 0; i < numSlots; i += slotsPerHour {
+		hour := (dayStart + time.Duration(i)*slot) / time.Hour % 24
+		color.Printf("<gray>%-*s</>", slotsPerHour, fmt.Sprintf("%02d", hour))
+	}
+	fmt.Printf("\033[K\n")
+
+	projects := make(map[int64]*model.Project)
+
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		label := d.Format("Mon Jan 02")
+		if absences[d.Format("2006-01-02")] != nil {
+			color.Printf("<yellow>%s</> <gray>┃</>", label)
+		} else if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			color.Printf("<gray>%s ┃</>", label)
+		} else {
+			color.Printf("<green>%s</> <gray>┃</>", label)
+		}
+
+		for i := 0; i < numSlots; i++ {
+			offset := dayStart + time.Duration(i)*slot
+			slotStart := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, int(offset), time.Local)
+			slotEnd := slotStart.Add(slot)
+
+			covered := make(map[int64]time.Duration)
+			var bestProject *model.Project
+			var total time.Duration
+			for _, f := range frames {
+				o := overlap(f, slotStart, slotEnd)
+				if o == 0 {
+					continue
+				}
+				p := f.Task.Project
+				covered[p.Id] += o
+				total += o
+				if bestProject == nil || covered[p.Id] > covered[bestProject.Id] {
+					bestProject = p
+				}
+			}
+
+			switch {
+			case bestProject == nil && i%slotsPerHour == 0:
+				color.Printf("<gray>·</>")
+			case bestProject == nil:
+				fmt.Printf(" ")
+			case total*2 >= slot:
+				projects[bestProject.Id] = bestProject
+				color.Printf("<%s>█</>", blockColor(bestProject))
+			default:
+				projects[bestProject.Id] = bestProject
+				color.Printf("<%s>░</>", blockColor(bestProject))
+			}
+		}
+
+		var dayTotal time.Duration
+		for _, f := range frames {
+			dayTotal += overlap(f, d, d.AddDate(0, 0, 1))
+		}
+		color.Printf("<gray>┃</> %6s\033[K\n", util.GetHours(dayTotal))
+	}
+
+	projectIds := make([]int64, 0, len(projects))
+	for id := range projects {
+		projectIds = append(projectIds, id)
+	}
+	sort.Slice(projectIds, func(i, j int) bool { return projects[projectIds[i]].Name < projects[projectIds[j]].Name })
+
+	fmt.Printf("\033[K\n")
+	for _, id := range projectIds {
+		color.Printf("<%s>█</> %s  ", blockColor(projects[id]), projectTag(projects[id]))
+	}
+	fmt.Printf("\033[J\n")
+}
+
+// The color of the blocks for a project, which is its own color if it has
+// one so it matches its name elsewhere
+func blockColor(p *model.Project) string {
+	if p.Color != "" {
+		return p.Color
+	}
+	return view.ProjectColor(p.Id)
+}
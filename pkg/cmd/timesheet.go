@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/completion"
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/util"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+var Timesheet = &cli.Command{
+	Name:         "timesheet",
+	Usage:        "Display a weekly timesheet of time spent on tasks for each day",
+	ArgsUsage:    "[project] [task]",
+	BashComplete: completion.ProjectTaskCompletion,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "week",
+			Aliases: []string{"w"},
+			Usage:   "Week to display (eg. 2026-w42 or w42, defaults to the current week)",
+		},
+		&cli.BoolFlag{
+			Name:  "csv",
+			Usage: "Output CSV format",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "Output JSON format",
+		},
+		&cli.BoolFlag{
+			Name:  "md",
+			Usage: "Output Markdown table format",
+		},
+		timezoneFlag,
+	},
+	Before: setTimezone,
+	Action: func(c *cli.Context) error {
+		firstDay := db.GetSettings().FirstDayOfWeek
+
+		weekStart := util.StartOfWeek(time.Now(), firstDay)
+		if v := c.String("week"); v != "" {
+			weekStart = util.WeekFromShorthand(v, firstDay)
+		}
+		weekEnd := weekStart.AddDate(0, 0, 7)
+
+		var days [7]time.Time
+		dayIndex := make(map[string]int)
+		for i := range days {
+			days[i] = weekStart.AddDate(0, 0, i)
+			dayIndex[days[i].Format("2006-01-02")] = i
+		}
+
+		type row struct {
+			projectName  string
+			taskName     string
+			projectColor string
+			taskColor    string
+			ref          string
+			days         [7]time.Duration
+			total        time.Duration
+		}
+
+		rows := make(map[int64]*row)
+		var dayTotals [7]time.Duration
+		var total time.Duration
+
+		for _, f := range model.GetFramesMatching(weekStart, weekEnd, c.Args().Get(0), c.Args().Get(1)) {
+			start, end := f.StartTime.Local(), f.EndTime.Local()
+			if f.EndTime.IsZero() {
+				end = time.Now()
+			}
+			if start.Before(weekStart) {
+				start = weekStart
+			}
+			if end.After(weekEnd) {
+				end = weekEnd
+			}
+
+			r := rows[f.Task.Id]
+			if r == nil {
+				r = &row{
+					projectName:  f.Task.Project.Name,
+					taskName:     f.Task.Name,
+					projectColor: f.Task.Project.Color,
+					taskColor:    f.Task.Color,
+					ref:          f.Task.GetRef(),
+				}
+				rows[f.Task.Id] = r
+			}
+
+			// Frames spanning midnight count towards each day they cover
+			for _, d := range util.SplitByDay(start, end) {
+				i, ok := dayIndex[d.Date.Format("2006-01-02")]
+				if !ok {
+					continue
+				}
+				r.days[i] += d.Duration
+				r.total += d.Duration
+				dayTotals[i] += d.Duration
+				total += d.Duration
+			}
+		}
+
+		var sorted []*row
+		for _, r := range rows {
+			sorted = append(sorted, r)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].projectName != sorted[j].projectName {
+				return sorted[i].projectName < sorted[j].projectName
+			}
+			return sorted[i].taskName < sorted[j].taskName
+		})
+
+		year, week := weekStart.AddDate(0, 0, 3).ISOWeek()
+
+		switch {
+		case c.Bool("json"):
+			type jsonRow struct {
+				Project string     `json:"project"`
+				Task    string     `json:"task"`
+				Days    [7]float64 `json:"days"`
+				Total   float64    `json:"total"`
+				Ref     string     `json:"ref,omitempty"`
+			}
+			out := struct {
+				Week   string     `json:"week"`
+				Days   [7]string  `json:"days"`
+				Rows   []jsonRow  `json:"rows"`
+				Totals [7]float64 `json:"totals"`
+				Total  float64    `json:"total"`
+			}{
+				Week:  fmt.Sprintf("%d-W%02d", year, week),
+				Rows:  []jsonRow{},
+				Total: total.Hours(),
+			}
+			for i, d := range days {
+				out.Days[i] = d.Format("2006-01-02")
+				out.Totals[i] = dayTotals[i].Hours()
+			}
+			for _, r := range sorted {
+				jr := jsonRow{Project: r.projectName, Task: r.taskName, Total: r.total.Hours(), Ref: r.ref}
+				for i, d := range r.days {
+					jr.Days[i] = d.Hours()
+				}
+				out.Rows = append(out.Rows, jr)
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(out); err != nil {
+				log.Fatalln("error outputting json:", err)
+			}
+
+		case c.Bool("csv"):
+			w := csv.NewWriter(os.Stdout)
+
+			header := []string{"Project", "Task"}
+			for _, d := range days {
+				header = append(header, d.Format("Mon 02"))
+			}
+			w.Write(append(header, "Total"))
+
+			for _, r := range sorted {
+				record := []string{r.projectName, r.taskName}
+				for _, d := range r.days {
+					record = append(record, fmt.Sprintf("%.2f", d.Hours()))
+				}
+				if err := w.Write(append(record, fmt.Sprintf("%.2f", r.total.Hours()))); err != nil {
+					log.Fatalln("error outputting csv:", err)
+				}
+			}
+
+			record := []string{"Total", ""}
+			for _, d := range dayTotals {
+				record = append(record, fmt.Sprintf("%.2f", d.Hours()))
+			}
+			w.Write(append(record, fmt.Sprintf("%.2f", total.Hours())))
+
+			w.Flush()
+
+		case c.Bool("md"):
+			header := "| Project | Task |"
+			separator := "| --- | --- |"
+			for _, d := range days {
+				header += " " + d.Format("Mon 02") + " |"
+				separator += " ---: |"
+			}
+			fmt.Println(header + " Total |")
+			fmt.Println(separator + " ---: |")
+
+			for _, r := range sorted {
+				line := fmt.Sprintf("| %s | %s |", r.projectName, r.taskName)
+				for _, d := range r.days {
+					line += fmt.Sprintf(" %.2f |", d.Hours())
+				}
+				fmt.Printf("%s %.2f |\n", line, r.total.Hours())
+			}
+
+			line := "| **Total** | |"
+			for _, d := range dayTotals {
+				line += fmt.Sprintf(" **%.2f** |", d.Hours())
+			}
+			fmt.Printf("%s **%.2f** |\n", line, total.Hours())
+
+		default:
+			longestProject := len("Total")
+			longestTask := 0
+			for _, r := range sorted {
+				if len(r.projectName) > longestProject {
+					longestProject = len(r.projectName)
+				}
+				if len(r.taskName) > longestTask {
+					longestTask = len(r.taskName)
+				}
+			}
+
+			printHours := func(d time.Duration) {
+				if d == 0 {
+					color.Printf(view.TimesheetNoHours, "-")
+				} else {
+					fmt.Printf("%7s", util.GetHours(d))
+				}
+			}
+
+			color.Printf(view.TimesheetWeek, week, days[0].Format("Mon Jan 02"), days[6].Format("Mon Jan 02 2006"))
+			fmt.Printf("%-*s %-*s", longestProject, "", longestTask, "")
+			for _, d := range days {
+				color.Printf(view.TimesheetDay, d.Format("Mon 02"))
+			}
+			color.Printf(view.TimesheetTotalHeader, "Total")
+
+			var prevProject string
+			for _, r := range sorted {
+				projectName := ""
+				if r.projectName != prevProject {
+					projectName = r.projectName
+					prevProject = r.projectName
+				}
+				color.Printf(
+					view.TimesheetProjectTask,
+					view.ProjectNameColor(r.projectColor),
+					longestProject,
+					projectName,
+					view.TaskColor(r.taskColor),
+					longestTask,
+					r.taskName,
+				)
+				for _, d := range r.days {
+					printHours(d)
+				}
+				fmt.Print(" ")
+				printHours(r.total)
+				fmt.Println()
+			}
+
+			fmt.Printf("%-*s %-*s", longestProject, "Total", longestTask, "")
+			for _, d := range dayTotals {
+				printHours(d)
+			}
+			fmt.Print(" ")
+			printHours(total)
+			fmt.Println()
+		}
+
+		return nil
+	},
+}
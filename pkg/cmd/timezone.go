@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/urfave/cli/v2"
+)
+
+var timezoneFlag = &cli.StringFlag{
+	Name:  "tz",
+	Usage: "Timezone to display times and split days in (eg. --tz Europe/Berlin)",
+}
+
+// Override the configured display timezone with the --tz flag
+func setTimezone(c *cli.Context) error {
+	if v := c.String("tz"); v != "" {
+		if err := db.SetTimezone(v); err != nil {
+			log.Fatalf("Bad timezone: %s", v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/model"
+)
+
+// taskTime is the time spent on a task itself, not including its subtasks
+type taskTime struct {
+	Task      *model.Task
+	StartTime time.Time
+	EndTime   time.Time
+	Duration  time.Duration
+}
+
+// taskNode is a task in a tree of tasks, with the time spent on its subtasks
+// rolled up into it
+type taskNode struct {
+	Task      *model.Task
+	Depth     int
+	StartTime time.Time
+	EndTime   time.Time
+	Duration  time.Duration
+
+	// The index of the task's own time, or -1 if only its subtasks have time
+	Index int
+
+	parent   *taskNode
+	children []*taskNode
+}
+
+// Name returns the name of the task indented by its depth, without the names
+// of its parents
+func (n *taskNode) Name() string {
+	if n.parent == nil {
+		return n.Task.Name
+	}
+	return strings.Repeat("  ", n.Depth) + strings.TrimPrefix(n.Task.Name, n.parent.Task.Name+"/")
+}
+
+// getTaskTree arranges the times into a tree of tasks and their subtasks,
+// returning each task after its parent. Tasks are kept in the order of the
+// times, and parents which have no time of their own are added before their
+// first subtask.
+func getTaskTree(times []taskTime) (nodes []*taskNode) {
+	var roots []*taskNode
+	byId := make(map[int64]*taskNode)
+
+	var getNode func(t *model.Task) *taskNode
+	getNode = func(t *model.Task) *taskNode {
+		if n := byId[t.Id]; n != nil {
+			return n
+		}
+		n := &taskNode{Task: t, Index: -1}
+		byId[t.Id] = n
+		if t.ParentId != 0 {
+			parent := model.GetTaskById(t.ParentId)
+			n.parent = getNode(&parent)
+			n.parent.children = append(n.parent.children, n)
+		} else {
+			roots = append(roots, n)
+		}
+		return n
+	}
+
+	for i, tt := range times {
+		n := getNode(tt.Task)
+		n.Index = i
+		for ; n != nil; n = n.parent {
+			n.Duration += tt.Duration
+			if !tt.StartTime.IsZero() && (n.StartTime.IsZero() || tt.StartTime.Before(n.StartTime)) {
+				n.StartTime = tt.StartTime
+			}
+			if tt.EndTime.After(n.EndTime) {
+				n.EndTime = tt.EndTime
+			}
+		}
+	}
+
+	var walk func(n *taskNode, depth int)
+	walk = func(n *taskNode, depth int) {
+		n.Depth = depth
+		nodes = append(nodes, n)
+		for _, child := range n.children {
+			walk(child, depth+1)
+		}
+	}
+	for _, n := range roots {
+		walk(n, 0)
+	}
+	return
+}
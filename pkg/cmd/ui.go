@@ -0,0 +1,518 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/cleanup"
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/hooks"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/util"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	uiFocusTasks = iota
+	uiFocusFrames
+)
+
+type ui struct {
+	state       *model.State
+	tasks       []*model.Task
+	frames      []*model.Frame
+	week        []*model.Frame
+	focus       int
+	taskCursor  int
+	frameCursor int
+	message     string
+
+	// Line input shown at the bottom of the screen
+	prompt  string
+	input   string
+	onInput func(string)
+}
+
+var Ui = &cli.Command{
+	Name:  "ui",
+	Usage: "Open an interactive dashboard",
+	Action: func(c *cli.Context) error {
+		restore, err := util.MakeRaw()
+		if err != nil {
+			log.Fatalln("unable to read keys from the terminal:", err)
+		}
+
+		exit := func() {
+			fmt.Printf("\033[?25h\033[?1049l")
+			restore()
+		}
+		cleanup.SetCleanupFn(exit)
+		defer exit()
+
+		fmt.Printf("\033[?1049h\033[?25l")
+
+		// Hooks would draw over the dashboard
+		hooks.Stdout = ioutil.Discard
+		hooks.Stderr = ioutil.Discard
+
+		keys := make(chan string)
+		go readKeys(keys)
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		u := &ui{}
+		showHookError = func(msg string, err error) {
+			u.message = color.Sprintf(msg, err)
+		}
+		for {
+			u.load()
+			u.draw()
+			select {
+			case key := <-keys:
+				if !u.handleKey(key) {
+					return nil
+				}
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// How long to wait for the rest of an escape sequence after esc, so a lone
+// esc is sent without waiting for the next key press
+const escTimeout = 50 * time.Millisecond
+
+// Read key presses from stdin, translating escape sequences for the arrow
+// keys and dropping other escape sequences whole. Other keys are read as runes
+// so characters which take more than one byte in UTF-8 are sent whole.
+func readKeys(keys chan<- string) {
+	defer close(keys)
+
+	runes := make(chan rune)
+	go func() {
+		defer close(runes)
+		r := bufio.NewReader(os.Stdin)
+		for {
+			c, _, err := r.ReadRune()
+			if err != nil {
+				return
+			}
+			runes <- c
+		}
+	}()
+
+	// Read the next rune of an escape sequence, returning false if it
+	// doesn't arrive in time
+	next := func() (rune, bool) {
+		select {
+		case c, ok := <-runes:
+			return c, ok
+		case <-time.After(escTimeout):
+			return 0, false
+		}
+	}
+
+	var c rune
+	var ok bool
+	read := true
+	for {
+		if read {
+			if c, ok = <-runes; !ok {
+				return
+			}
+		}
+		read = true
+		if c != 0x1b {
+			keys <- string(c)
+			continue
+		}
+
+		c, ok = next()
+		if !ok || (c != '[' && c != 'O') {
+			keys <- "esc"
+			// The rune after esc is a key of its own (eg. alt+key)
+			read = !ok
+			continue
+		}
+
+		// SS3 sequences (eg. esc O A) end after one byte, and CSI sequences
+		// (eg. esc [ 1 ; 5 A) end with the first byte which isn't a parameter
+		csi := c == '['
+		for {
+			if c, ok = next(); !ok || !csi || c < 0x20 || c > 0x3f {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		switch c {
+		case 'A':
+			keys <- "up"
+		case 'B':
+			keys <- "down"
+		}
+	}
+}
+
+func (u *ui) load() {
+	u.state = model.GetState()
+
+	u.tasks = nil
+	projects := model.ActiveProjects(model.GetProjects())
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	for _, p := range projects {
+		tasks := model.ActiveTasks(p.GetTasks())
+		sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name < tasks[j].Name })
+		u.tasks = append(u.tasks, tasks...)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	u.frames = model.GetFrames(today, today.AddDate(0, 0, 1))
+
+	weekStart := util.StartOfWeek(now, db.GetSettings().FirstDayOfWeek)
+	u.week = model.GetFrames(weekStart, weekStart.AddDate(0, 0, 7))
+
+	u.taskCursor = clamp(u.taskCursor, 0, len(u.tasks)-1)
+	u.frameCursor = clamp(u.frameCursor, 0, len(u.frames)-1)
+}
+
+func (u *ui) draw() {
+	width, height := util.TerminalSize()
+	var lines []string
+
+	header := func(title string) {
+		rule := strings.Repeat("─", max(0, width-len(title)-4))
+		lines = append(lines, color.Sprintf("<gray>──</> <green>%s</> <gray>%s</>", title, rule))
+	}
+
+	if u.state.Running {
+		lines = append(lines, color.Sprintf(
+			view.RunningProjectTaskElapsedTotal,
+			projectTag(u.state.Task.Project),
+			taskTag(&u.state.Task),
+			util.GetHours(u.state.TimeElapsed),
+			util.GetHours(u.state.Task.GetTotal()),
+		))
+		lines = append(lines, color.Sprintf(
+			view.StartedAtTimeElapsed,
+			u.state.StartTime.Format("15:04"),
+			u.state.TimeElapsed.Round(time.Second),
+		))
+	} else {
+		lines = append(lines, view.NotRunning, "")
+	}
+
+	// Today's frames
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	var todayTotal time.Duration
+	for _, f := range u.frames {
+		todayTotal += f.DurationBetween(today, today.AddDate(0, 0, 1))
+	}
+	header(fmt.Sprintf("Today (%s)", util.GetHours(todayTotal)))
+	for i, f := range u.frames {
+		end := "     "
+		if !f.IsRunning() {
+			end = f.EndTime.Format("15:04")
+		}
+		lines = append(lines, color.Sprintf(
+			"%s<green>%s - %s</> %6s %s <%s>%s</> <gray>%s</>",
+			u.cursor(uiFocusFrames, i == u.frameCursor),
+			f.StartTime.Format("15:04"),
+			end,
+			util.GetHours(f.DurationBetween(today, today.AddDate(0, 0, 1))),
+			projectTag(f.Task.Project),
+			view.TaskColor(f.Task.Color),
+			f.Task.Name,
+			f.Note,
+		))
+	}
+
+	// Totals per project for the week
+	weekStart := util.StartOfWeek(now, db.GetSettings().FirstDayOfWeek)
+	weekTotals := make(map[string]time.Duration)
+	weekColors := make(map[string]string)
+	var weekTotal, maxProject time.Duration
+	for _, f := range u.week {
+		d := f.DurationBetween(weekStart, weekStart.AddDate(0, 0, 7))
+		weekTotals[f.Task.Project.Name] += d
+		weekColors[f.Task.Project.Name] = f.Task.Project.Color
+		weekTotal += d
+		if weekTotals[f.Task.Project.Name] > maxProject {
+			maxProject = weekTotals[f.Task.Project.Name]
+		}
+	}
+	var weekProjects []string
+	for p := range weekTotals {
+		weekProjects = append(weekProjects, p)
+	}
+	sort.Strings(weekProjects)
+	header(fmt.Sprintf("This week (%s)", util.GetHours(weekTotal)))
+	for _, p := range weekProjects {
+		lines = append(lines, color.Sprintf(
+			"  %7s %s %s",
+			util.GetHours(weekTotals[p]),
+			getBar(weekTotals[p], maxProject, 20, view.ProjectNameColor(weekColors[p])),
+			view.ProjectName(p, weekColors[p]),
+		))
+	}
+
+	// Projects and tasks, scrolled to keep the cursor in view
+	header("Projects")
+	available := height - len(lines) - 3
+	var taskLines []string
+	cursorLine := 0
+	var prevProject string
+	for i, t := range u.tasks {
+		if t.Project.Name != prevProject {
+			taskLines = append(taskLines, color.Sprintf("  %s", projectTag(t.Project)))
+			prevProject = t.Project.Name
+		}
+		if i == u.taskCursor {
+			cursorLine = len(taskLines)
+		}
+		running := ""
+		if u.state.Running && u.state.Task.Id == t.Id {
+			running = color.Sprintf(" <green>●</>")
+		}
+		taskLines = append(taskLines, color.Sprintf("%s  <%s>%s</>%s", u.cursor(uiFocusTasks, i == u.taskCursor), view.TaskColor(t.Color), t.Name, running))
+	}
+	offset := 0
+	if available > 0 && cursorLine >= available {
+		offset = cursorLine - available + 1
+	}
+	for i := offset; i < len(taskLines) && i-offset < available; i++ {
+		lines = append(lines, taskLines[i])
+	}
+
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+
+	if u.onInput != nil {
+		lines = append(lines, color.Sprintf("<cyan>%s</> %s█", u.prompt, u.input))
+	} else {
+		lines = append(lines, u.message)
+	}
+	lines = append(lines, color.Sprintf("<gray>↑↓ move  tab switch pane  s start  x stop  e edit  n note  q quit</>"))
+
+	fmt.Printf("\033[H")
+	for i, line := range lines {
+		// Messages shared with other commands end with a newline and clear
+		// the rest of the screen, so strip those to avoid flickering
+		line = strings.ReplaceAll(strings.TrimRight(line, "\n"), "\033[J", "")
+		if i == len(lines)-1 {
+			fmt.Printf("%s\033[K", line)
+		} else {
+			fmt.Printf("%s\033[K\n", line)
+		}
+	}
+}
+
+func (u *ui) cursor(focus int, selected bool) string {
+	if !selected {
+		return "  "
+	}
+	if u.focus == focus {
+		return color.Sprintf("<cyan>›</> ")
+	}
+	return color.Sprintf("<gray>›</> ")
+}
+
+// Handle a key press, returning false to exit
+func (u *ui) handleKey(key string) bool {
+	if u.onInput != nil {
+		switch key {
+		case "\n", "\r":
+			onInput := u.onInput
+			u.onInput = nil
+			onInput(u.input)
+		case "esc":
+			u.onInput = nil
+		case "\x7f", "\b":
+			if r := []rune(u.input); len(r) > 0 {
+				u.input = string(r[:len(r)-1])
+			}
+		default:
+			if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
+				u.input += key
+			}
+		}
+		return true
+	}
+
+	u.message = ""
+
+	switch key {
+	case "", "q":
+		return false
+	case "\t":
+		u.focus = (u.focus + 1) % 2
+	case "up", "k":
+		if u.focus == uiFocusTasks {
+			u.taskCursor = clamp(u.taskCursor-1, 0, len(u.tasks)-1)
+		} else {
+			u.frameCursor = clamp(u.frameCursor-1, 0, len(u.frames)-1)
+		}
+	case "down", "j":
+		if u.focus == uiFocusTasks {
+			u.taskCursor = clamp(u.taskCursor+1, 0, len(u.tasks)-1)
+		} else {
+			u.frameCursor = clamp(u.frameCursor+1, 0, len(u.frames)-1)
+		}
+	case "s", "\n", "\r":
+		u.start()
+	case "x":
+		u.stop()
+	case "e":
+		u.edit()
+	case "n":
+		u.note()
+	}
+	return true
+}
+
+// Start the selected task, stopping the running task first
+func (u *ui) start() {
+	if len(u.tasks) == 0 {
+		return
+	}
+	t := u.tasks[u.taskCursor]
+	if u.state.Running && u.state.Task.Id == t.Id {
+		u.message = color.Sprintf(view.AlreadyRunningProjectTaskElapsedTotal, projectTag(t.Project), taskTag(t), util.GetHours(u.state.TimeElapsed), util.GetHours(t.GetTotal()))
+		return
+	}
+	now := time.Now()
+	if !runPreHook(hooks.PreStart, hooks.ActionStart, &model.Frame{Task: t, StartTime: now}) {
+		return
+	}
+	stopFrame(now)
+	u.message = color.Sprintf(view.RunningProjectTaskTotal, projectTag(t.Project), taskTag(t), util.GetHours(t.GetTotal()))
+	startFrame(t.Project, t, t.Name, now)
+}
+
+func (u *ui) stop() {
+	if !u.state.Running {
+		u.message = view.NotRunning
+		return
+	}
+	u.message = color.Sprintf(
+		view.StoppedProjectTaskElapsedTotal,
+		projectTag(u.state.Task.Project),
+		taskTag(&u.state.Task),
+		util.GetHours(u.state.TimeElapsed),
+		util.GetHours(u.state.Task.GetTotal()),
+	)
+	stopFrame(time.Now())
+}
+
+// The frame to edit, either the selected frame or the running frame
+func (u *ui) selectedFrame() *model.Frame {
+	if u.focus == uiFocusFrames && len(u.frames) != 0 {
+		return u.frames[u.frameCursor]
+	}
+	for _, f := range u.frames {
+		if f.IsRunning() {
+			return f
+		}
+	}
+	return nil
+}
+
+func (u *ui) edit() {
+	f := u.selectedFrame()
+	if f == nil {
+		u.message = view.NotRunning
+		return
+	}
+
+	u.prompt = "Start - end (15:04 - 15:04):"
+	u.input = f.StartTime.Format("15:04")
+	if !f.IsRunning() {
+		u.input += " - " + f.EndTime.Format("15:04")
+	}
+	u.onInput = func(v string) {
+		parts := strings.Split(v, "-")
+		startTime, err := timeOnDate(f.StartTime, parts[0])
+		if err != nil {
+			u.message = color.Sprintf("<red>Bad time:</> %s", parts[0])
+			return
+		}
+		endTime := f.EndTime
+		if len(parts) > 1 && !f.IsRunning() {
+			if endTime, err = timeOnDate(f.StartTime, parts[1]); err != nil {
+				u.message = color.Sprintf("<red>Bad time:</> %s", parts[1])
+				return
+			}
+			if endTime.Before(startTime) {
+				endTime = endTime.AddDate(0, 0, 1)
+			}
+		}
+		edited := *f
+		edited.StartTime = startTime
+		edited.EndTime = endTime
+		if msg := checkFrameTimes(&edited); msg != "" {
+			u.message = color.Sprintf(msg)
+			return
+		}
+		*f = edited
+		f.Update()
+		u.message = "Updated frame"
+		runPostHook(hooks.PostEdit, hooks.ActionEdit, f)
+	}
+}
+
+func (u *ui) note() {
+	f := u.selectedFrame()
+	if f == nil {
+		u.message = view.NotRunning
+		return
+	}
+
+	u.prompt = "Note:"
+	u.input = f.Note
+	u.onInput = func(v string) {
+		f.Note = strings.TrimSpace(v)
+		f.Update()
+		u.message = "Updated note"
+		runPostHook(hooks.PostEdit, hooks.ActionEdit, f)
+	}
+}
+
+// Parse a time of day (eg. 09:30) on the same date as t
+func timeOnDate(t time.Time, v string) (time.Time, error) {
+	tod, err := time.Parse("15:04", strings.TrimSpace(v))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), tod.Hour(), tod.Minute(), 0, 0, time.Local), nil
+}
+
+func clamp(v, min, max int) int {
+	if v > max {
+		v = max
+	}
+	if v < min {
+		v = min
+	}
+	return v
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
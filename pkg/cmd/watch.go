@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/cleanup"
+)
+
+// Periodically redraw the output of print in the alternate screen. On SIGINT
+// the output is printed once more after switching back to the main screen.
+func watch(print func(), interval time.Duration) {
+	cleanup.SetCleanupFn(print)
+
+	fmt.Printf("\033[?1049h\033[H")
+	for {
+		print()
+		time.Sleep(interval)
+		fmt.Printf("\033[H")
+	}
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/jasonwoodland/track/pkg/completion"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/presenter"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/jasonwoodland/track/pkg/webhook"
+	"github.com/urfave/cli/v2"
+)
+
+var WebhookCmds = &cli.Command{
+	Name:         "webhook",
+	Usage:        "Manage URLs which are sent events when frames start and stop",
+	BashComplete: completion.CommandCompletion,
+	Subcommands: []*cli.Command{
+		{
+			Name:      "add",
+			Usage:     "Add a webhook",
+			ArgsUsage: "url",
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{
+					Name:    "event",
+					Aliases: []string{"e"},
+					Usage:   "Only send an event (" + strings.Join(webhook.Events, ", ") + "), can be given more than once",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.Args().Len() != 1 {
+					cli.ShowSubcommandHelp(c)
+					return nil
+				}
+
+				rawUrl := c.Args().Get(0)
+				if u, err := url.Parse(rawUrl); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					color.Printf(view.BadWebhookUrl, rawUrl)
+					return nil
+				}
+
+				events := c.StringSlice("event")
+				for _, e := range events {
+					if !isWebhookEvent(e) {
+						color.Printf(view.BadWebhookEvent, e, strings.Join(webhook.Events, ", "))
+						return nil
+					}
+				}
+
+				w := model.AddWebhook(rawUrl, events)
+				color.Printf(view.AddedWebhook, w.Id, w.Url, getWebhookEvents(w))
+				return nil
+			},
+		},
+		{
+			Name:    "list",
+			Aliases: []string{"ls"},
+			Usage:   "List webhooks and the events waiting to be delivered",
+			Action: func(c *cli.Context) error {
+				for _, w := range model.GetWebhooks() {
+					color.Printf(view.WebhookUrlEvents, w.Id, w.Url, getWebhookEvents(w))
+					if n, lastError := w.GetPending(); n != 0 {
+						s := "s"
+						if n == 1 {
+							s = ""
+						}
+						color.Printf(view.WebhookPendingError, n, s, lastError)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "remove",
+			Aliases:   []string{"rm"},
+			Usage:     "Delete a webhook and any events waiting to be delivered to it",
+			ArgsUsage: "id",
+			Action: func(c *cli.Context) error {
+				w := getWebhookFromArgs(c)
+				if w == nil {
+					return nil
+				}
+
+				n, _ := w.GetPending()
+				s := "s"
+				if n == 1 {
+					s = ""
+				}
+
+				if !presenter.Confirm(color.Sprintf(view.ConfirmDeleteWebhook, w.Url, n, s), false) {
+					return nil
+				}
+
+				w.Remove()
+				color.Println(view.Deleted)
+				return nil
+			},
+		},
+		{
+			Name:      "test",
+			Usage:     "Send a test event to a webhook",
+			ArgsUsage: "id",
+			Action: func(c *cli.Context) error {
+				w := getWebhookFromArgs(c)
+				if w == nil {
+					return nil
+				}
+
+				if err := webhook.Test(w); err != nil {
+					color.Printf(view.SendFailed, err)
+					return nil
+				}
+				color.Printf(view.SentTestEvent, w.Url)
+				return nil
+			},
+		},
+	},
+}
+
+func getWebhookFromArgs(c *cli.Context) *model.Webhook {
+	if c.Args().Len() != 1 {
+		cli.ShowSubcommandHelp(c)
+		return nil
+	}
+
+	id, _ := strconv.ParseInt(c.Args().Get(0), 10, 64)
+	w := model.GetWebhookById(id)
+	if w == nil {
+		color.Printf(view.WebhookDoesNotExist, c.Args().Get(0))
+	}
+	return w
+}
+
+func getWebhookEvents(w *model.Webhook) string {
+	if len(w.Events) == 0 {
+		return "all events"
+	}
+	return strings.Join(w.Events, ", ")
+}
+
+func isWebhookEvent(event string) bool {
+	for _, e := range webhook.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -2,23 +2,82 @@ package completion
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/jasonwoodland/track/pkg/model"
 	"github.com/urfave/cli/v2"
 )
 
+// Print a completion candidate with an optional description, in the format
+// expected by the shell the completion script was generated for
+func printEntry(value, desc string) {
+	switch shell() {
+	case "bash":
+		fmt.Println(value)
+	case "fish":
+		if desc != "" {
+			fmt.Printf("%s\t%s\n", value, desc)
+		} else {
+			fmt.Println(value)
+		}
+	default:
+		value = strings.ReplaceAll(value, ":", "\\:")
+		if desc != "" {
+			fmt.Printf("%s:%s\n", value, desc)
+		} else {
+			fmt.Println(value)
+		}
+	}
+}
+
+// The shell being completed, set by the scripts printed by the completion
+// command. Older zsh scripts only set the urfave/cli zsh variable.
+func shell() string {
+	if s := os.Getenv("_TRACK_COMPLETION_SHELL"); s != "" {
+		return s
+	}
+	if os.Getenv("_CLI_ZSH_AUTOCOMPLETE_HACK") == "1" {
+		return "zsh"
+	}
+	return "bash"
+}
+
+// Complete the subcommands of the app or command being completed
+func CommandCompletion(c *cli.Context) {
+	if ShowFlagCompletion(c) {
+		return
+	}
+
+	for _, cmd := range c.App.Commands {
+		if cmd.Hidden {
+			continue
+		}
+		for _, n := range cmd.Names() {
+			printEntry(n, cmd.Usage)
+		}
+	}
+}
+
+func ShellCompletion(c *cli.Context) {
+	if c.NArg() == 0 {
+		for _, s := range []string{"bash", "zsh", "fish"} {
+			printEntry(s, "")
+		}
+	}
+}
+
 func ShowFlagCompletion(c *cli.Context) bool {
 	if c.Args().Len() > 0 && c.Args().Get(c.Args().Len() - 1)[0] == '-' {
 		for _, f := range c.Command.Flags {
 			desc := f.String()[strings.Index(f.String(), "\t")+1:]
 			for _, n := range f.Names() {
 				if len(n) == 1 {
-					fmt.Printf("-%s", n)
+					printEntry("-"+n, desc)
 				} else {
-					fmt.Printf("--%s", n)
+					printEntry("--"+n, desc)
 				}
-				fmt.Printf(":%s\n", desc)
 			}
 		}
 		return true
@@ -32,66 +91,111 @@ func ProjectCompletion(c *cli.Context) {
 	}
 
 	if c.NArg() == 0 {
-		for _, p := range model.GetProjects() {
-			fmt.Println(p.Name)
+		for _, p := range model.ActiveProjects(model.GetProjects()) {
+			printEntry(p.Name, "")
 		}
 		return
 	}
 }
 
-func ProjectTaskCompletion(c *cli.Context) {
+func ClientCompletion(c *cli.Context) {
 	if ShowFlagCompletion(c) {
 		return
 	}
 
 	if c.NArg() == 0 {
-		for _, p := range model.GetProjects() {
-			fmt.Println(p.Name)
+		for _, cl := range model.GetClients() {
+			printEntry(cl.Name, "")
 		}
 		return
 	}
+}
 
-	p := model.GetProjectByName(c.Args().Get(0))
+// Complete an archived project, for unarchiving it
+func ArchivedProjectCompletion(c *cli.Context) {
+	if ShowFlagCompletion(c) {
+		return
+	}
 
-	if c.NArg() == 1 {
-		for _, t := range p.GetTasks() {
-			fmt.Println(t.Name)
+	if c.NArg() == 0 {
+		for _, p := range model.GetProjects() {
+			if p.Archived {
+				printEntry(p.Name, "")
+			}
 		}
 	}
 }
 
-func ProjectTaskProjectTaskCompletion(c *cli.Context) {
+// Complete a project then an archived task, for unarchiving it
+func ProjectArchivedTaskCompletion(c *cli.Context) {
 	if ShowFlagCompletion(c) {
 		return
 	}
 
 	if c.NArg() == 0 {
 		for _, p := range model.GetProjects() {
-			fmt.Println(p.Name)
+			printEntry(p.Name, "")
 		}
 		return
 	}
 
 	p := model.GetProjectByName(c.Args().Get(0))
 
-	if c.NArg() == 1 {
+	if c.NArg() == 1 && p != nil {
 		for _, t := range p.GetTasks() {
-			fmt.Println(t.Name)
+			if t.Archived {
+				printEntry(t.Name, "")
+			}
 		}
 	}
+}
 
-	if c.NArg() == 2 {
-		for _, p := range model.GetProjects() {
-			fmt.Println(p.Name)
+// Complete a project then a task. Tasks for the project of the current
+// directory are also completed in place of the project, since start can infer
+// the project.
+func ProjectTaskCompletion(c *cli.Context) {
+	if ShowFlagCompletion(c) {
+		return
+	}
+
+	if c.NArg() == 0 {
+		for _, p := range model.ActiveProjects(model.GetProjects()) {
+			printEntry(p.Name, "")
+		}
+		if p := model.GetProjectByName(model.GetProjectNameForDir(".")); p != nil && !p.Archived {
+			for _, t := range model.ActiveTasks(p.GetTasks()) {
+				printEntry(t.Name, p.Name)
+			}
 		}
 		return
 	}
 
-	p = model.GetProjectByName(c.Args().Get(0))
+	p := model.GetProjectByName(c.Args().Get(0))
 
-	if c.NArg() == 3 {
-		for _, t := range p.GetTasks() {
-			fmt.Println(t.Name)
+	if c.NArg() == 1 && p != nil {
+		for _, t := range model.ActiveTasks(p.GetTasks()) {
+			printEntry(t.Name, "")
+		}
+	}
+}
+
+func ProjectTaskProjectTaskCompletion(c *cli.Context) {
+	if ShowFlagCompletion(c) {
+		return
+	}
+
+	switch c.NArg() {
+	case 0, 2:
+		for _, p := range model.ActiveProjects(model.GetProjects()) {
+			printEntry(p.Name, "")
+		}
+	case 1, 3:
+		// The task is on the project before it
+		p := model.GetProjectByName(c.Args().Get(c.NArg() - 1))
+		if p != nil {
+			for _, t := range model.ActiveTasks(p.GetTasks()) {
+				printEntry(t.Name, "")
+			}
 		}
 	}
 }
@@ -102,85 +206,64 @@ func ProjectTaskFrameCompletion(c *cli.Context) {
 	}
 
 	if c.NArg() == 0 {
-		for _, p := range model.GetProjects() {
-			fmt.Println(p.Name)
+		for _, p := range model.ActiveProjects(model.GetProjects()) {
+			printEntry(p.Name, "")
 		}
 		return
 	}
 
 	p := model.GetProjectByName(c.Args().Get(0))
+	if p == nil {
+		return
+	}
 
 	if c.NArg() == 1 {
-		for _, t := range p.GetTasks() {
-			fmt.Println(t.Name)
+		for _, t := range model.ActiveTasks(p.GetTasks()) {
+			printEntry(t.Name, "")
 		}
 		return
 	}
 
 	t := p.GetTask(c.Args().Get(1))
 
-	if c.NArg() == 2 {
-		frames := t.GetFrames()
-		for i, f := range frames {
-			fmt.Printf(
-				"%v:%s - %s\n",
-				i,
-				f.StartTime.Format("Mon Jan 02 15:04"),
-				f.EndTime.Format("15:04"),
-			)
-		}
+	if c.NArg() == 2 && t != nil {
+		printFrameEntries(t)
 	}
 }
+
 func ProjectTaskFrameProjectTaskCompletion(c *cli.Context) {
-	if ShowFlagCompletion(c) {
+	if c.NArg() < 3 {
+		ProjectTaskFrameCompletion(c)
 		return
 	}
 
-	if c.NArg() < 3 {
-		if c.NArg() == 0 {
-			for _, p := range model.GetProjects() {
-				fmt.Println(p.Name)
-			}
-			return
-		}
-
-		p := model.GetProjectByName(c.Args().Get(0))
-
-		if c.NArg() == 1 {
-			for _, t := range p.GetTasks() {
-				fmt.Println(t.Name)
-			}
-			return
-		}
-
-		t := p.GetTask(c.Args().Get(1))
-
-		if c.NArg() == 2 {
-			frames := t.GetFrames()
-			for i, f := range frames {
-				fmt.Printf(
-					"%v:%s - %s\n",
-					i,
-					f.StartTime.Format("Mon Jan 02 15:04"),
-					f.EndTime.Format("15:04"),
-				)
-			}
-		}
+	if ShowFlagCompletion(c) {
+		return
 	}
 
 	if c.NArg() == 3 {
-		for _, p := range model.GetProjects() {
-			fmt.Println(p.Name)
+		for _, p := range model.ActiveProjects(model.GetProjects()) {
+			printEntry(p.Name, "")
 		}
 		return
 	}
 
-	p := model.GetProjectByName(c.Args().Get(0))
+	// The destination project comes after the frame
+	p := model.GetProjectByName(c.Args().Get(3))
 
-	if c.NArg() == 4 {
-		for _, t := range p.GetTasks() {
-			fmt.Println(t.Name)
+	if c.NArg() == 4 && p != nil {
+		for _, t := range model.ActiveTasks(p.GetTasks()) {
+			printEntry(t.Name, "")
 		}
-		return
+	}
+}
+
+// Print the frames of the task by their index, as used by the frame commands
+func printFrameEntries(t *model.Task) {
+	for i, f := range t.GetFrames() {
+		printEntry(
+			strconv.Itoa(i),
+			f.StartTime.Format("Mon Jan 02 15:04")+" - "+f.EndTime.Format("15:04"),
+		)
 	}
 }
@@ -0,0 +1,45 @@
+package db
+
+import "fmt"
+
+// SQL helpers for splitting frames at day boundaries, so that frames spanning
+// midnight are attributed to each day they cover rather than the day they
+// ended on. Dates are interpreted in the display timezone.
+
+// Epoch returns an SQL expression for the unix time of a time value
+func Epoch(expr string) string {
+	return fmt.Sprintf("cast(strftime('%%s', %s) as integer)", expr)
+}
+
+// LocalDayStart returns an SQL expression for the unix time of local midnight
+// at the start of a date (eg. 2026-10-19)
+func LocalDayStart(date string) string {
+	return fmt.Sprintf("local_day_start(%s)", date)
+}
+
+// LocalDayEnd returns an SQL expression for the unix time of local midnight
+// at the end of a date
+func LocalDayEnd(date string) string {
+	return fmt.Sprintf("local_day_end(%s)", date)
+}
+
+// Overlaps returns an SQL condition which is true when the frame overlaps
+// with the range between the unix times from and to. Running frames are
+// excluded.
+func Overlaps(frame, from, to string) string {
+	return fmt.Sprintf(
+		"(%s < %s and %s > %s)",
+		Epoch(frame+".start_time"), to,
+		Epoch(frame+".end_time"), from,
+	)
+}
+
+// ClippedSeconds returns an SQL expression for the number of seconds of the
+// frame which fall within the range between the unix times from and to
+func ClippedSeconds(frame, from, to string) string {
+	return fmt.Sprintf(
+		"max(0, min(%s, %s) - max(%s, %s))",
+		Epoch(frame+".end_time"), to,
+		Epoch(frame+".start_time"), from,
+	)
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"os"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestMain(m *testing.M) {
+	// Berlin changes from summer time on 2026-10-25, so that day is 25 hours
+	if err := SetTimezone("Europe/Berlin"); err != nil {
+		panic(err)
+	}
+	Open("file:db?mode=memory&cache=shared")
+	os.Exit(m.Run())
+}
+
+func localTime(v string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", v, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestClippedSeconds(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		date     string
+		overlaps bool
+		seconds  int64
+	}{
+		{"before midnight", "2026-10-14 23:00", "2026-10-15 01:00", "2026-10-14", true, 3600},
+		{"after midnight", "2026-10-14 23:00", "2026-10-15 01:00", "2026-10-15", true, 3600},
+		{"day before", "2026-10-14 23:00", "2026-10-15 01:00", "2026-10-13", false, 0},
+		{"day after", "2026-10-14 23:00", "2026-10-15 01:00", "2026-10-16", false, 0},
+		{"before dst change", "2026-10-24 22:00", "2026-10-25 04:00", "2026-10-24", true, 2 * 3600},
+		{"after dst change", "2026-10-24 22:00", "2026-10-25 04:00", "2026-10-25", true, 5 * 3600},
+		{"whole dst day", "2026-10-25 00:00", "2026-10-26 00:00", "2026-10-25", true, 25 * 3600},
+		{"end of month", "2026-10-31 22:00", "2026-11-01 02:00", "2026-10-31", true, 2 * 3600},
+		{"start of month", "2026-10-31 22:00", "2026-11-01 02:00", "2026-11-01", true, 2 * 3600},
+		{"ends at midnight", "2026-10-14 22:00", "2026-10-15 00:00", "2026-10-15", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var overlaps bool
+			var seconds int64
+			err := Db.QueryRow(`
+				with f(start_time, end_time) as (values(?1, ?2))
+				select
+					`+Overlaps("f", LocalDayStart("?3"), LocalDayEnd("?3"))+`,
+					`+ClippedSeconds("f", LocalDayStart("?3"), LocalDayEnd("?3"))+`
+				from f
+			`, FormatTime(localTime(tt.start)), FormatTime(localTime(tt.end)), tt.date).Scan(&overlaps, &seconds)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if overlaps != tt.overlaps {
+				t.Errorf("overlaps = %v, want %v", overlaps, tt.overlaps)
+			}
+			if seconds != tt.seconds {
+				t.Errorf("seconds = %d, want %d", seconds, tt.seconds)
+			}
+		})
+	}
+}
@@ -2,16 +2,76 @@ package db
 
 import (
 	"database/sql"
+	"log"
 
 	"github.com/adrg/xdg"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 var Db *sql.DB
 
+// Fatal is called with errors from the database, and exits. The server
+// replaces it so a failed query only fails the request it was made for.
+var Fatal = func(err error) {
+	log.Fatal(err)
+}
+
+// Querier is implemented by *sql.DB and *sql.Tx, so models can make the same
+// queries in or out of a transaction
+type Querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Transaction runs fn in a transaction, which is committed if fn returns nil
+// and rolled back otherwise
+func Transaction(fn func(tx *sql.Tx) error) error {
+	tx, err := Db.Begin()
+	if err != nil {
+		Fatal(err)
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		Fatal(err)
+	}
+	return nil
+}
+
+func init() {
+	// Register functions for finding day boundaries in the display timezone,
+	// which SQLite's own date functions don't know about. Foreign keys are
+	// enabled on each connection so deletes cascade to tasks and frames.
+	sql.Register("sqlite3_track", &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if _, err := conn.Exec("pragma foreign_keys = on", nil); err != nil {
+				return err
+			}
+			if err := conn.RegisterFunc("local_day_start", localDayStart, true); err != nil {
+				return err
+			}
+			return conn.RegisterFunc("local_day_end", localDayEnd, true)
+		},
+	})
+}
+
 func OpenDb() {
 	dbFilePath, _ := xdg.DataFile("track-cli/db.sqlite3")
-	Db, _ = sql.Open("sqlite3", dbFilePath)
+
+	// Wait for other processes writing to the database, such as track serve,
+	// rather than failing, and let them read while another is writing
+	Open(dbFilePath + "?_busy_timeout=5000&_journal_mode=WAL")
+	if settings.Timezone != "" {
+		SetTimezone(settings.Timezone)
+	}
+}
+
+// Open opens the database with the data source name, which can be a file or
+// an in-memory database, and migrates it to the latest schema
+func Open(dataSourceName string) {
+	Db, _ = sql.Open("sqlite3_track", dataSourceName)
 	getSettings()
 	migrateDb()
 }
@@ -1,5 +1,7 @@
 package db
 
+import "strings"
+
 type Migration struct {
 	Version int
 	Up      func()
@@ -53,6 +55,183 @@ var migrations = []Migration{
 			`)
 		},
 	},
+	{
+		Version: 2,
+		Up: func() {
+			Db.Exec(`
+				alter table task add column estimate integer;
+			`)
+			Db.Exec(`
+				alter table project add column budget integer;
+			`)
+		},
+	},
+	{
+		Version: 3,
+		Up: func() {
+			Db.Exec(`
+				create table if not exists absence (
+					id integer primary key,
+					type text,
+					start_date text,
+					end_date text,
+					description text
+				);
+			`)
+
+			// Holidays and leave were previously stored as lists of dates in
+			// the settings
+			for key, absenceType := range map[string]string{"HOLIDAYS": "holiday", "LEAVE": "vacation"} {
+				for _, d := range strings.Split(GetSetting(key), ",") {
+					if d = strings.TrimSpace(d); d != "" {
+						Db.Exec(
+							"insert into absence (type, start_date, end_date) values ($1, $2, $2)",
+							absenceType,
+							d,
+						)
+					}
+				}
+				DeleteSetting(key)
+			}
+		},
+	},
+	{
+		Version: 4,
+		Up: func() {
+			// Times were previously stored with the local offset they were
+			// recorded in
+			Db.Exec(`
+				update frame set
+					start_time = strftime('%Y-%m-%dT%H:%M:%SZ', start_time),
+					end_time = strftime('%Y-%m-%dT%H:%M:%SZ', end_time)
+			`)
+		},
+	},
+	{
+		Version: 5,
+		Up: func() {
+			Db.Exec(`
+				alter table frame add column note text;
+			`)
+		},
+	},
+	{
+		Version: 6,
+		Up: func() {
+			Db.Exec(`
+				create table if not exists webhook (
+					id integer primary key,
+					url text,
+					events text
+				);
+			`)
+
+			// Events are queued in the outbox until they're delivered, so
+			// they aren't lost while the receiver is down. Ids aren't reused
+			// so receivers can use them to ignore retries.
+			Db.Exec(`
+				create table if not exists webhook_outbox (
+					id integer primary key autoincrement,
+					webhook_id integer,
+					event text,
+					payload text,
+					attempts integer default 0,
+					next_attempt text,
+					last_error text,
+
+					foreign key(webhook_id) references webhook(id) on delete cascade
+				);
+			`)
+		},
+	},
+	{
+		Version: 7,
+		Up: func() {
+			Db.Exec(`
+				alter table project add column dir text;
+			`)
+		},
+	},
+	{
+		Version: 8,
+		Up: func() {
+			// Frames imported from calendars keep the event's UID so they
+			// aren't imported twice
+			Db.Exec(`
+				alter table frame add column uid text;
+			`)
+			Db.Exec(`
+				create unique index if not exists frame_uid on frame (uid);
+			`)
+		},
+	},
+	{
+		Version: 9,
+		Up: func() {
+			Db.Exec(`
+				alter table task add column parent_id integer references task(id) on delete cascade;
+			`)
+
+			// The path of each task, which is the name of the task prefixed
+			// with the names of its parents (eg. frontend/login/oauth)
+			Db.Exec(`
+				create view if not exists task_path(id, path) as
+				with recursive p(id, path) as (
+					select id, name from task where parent_id is null
+					union all
+					select t.id, p.path || '/' || t.name from task t join p on t.parent_id = p.id
+				)
+				select id, path from p;
+			`)
+
+			// Each task paired with itself and each of its subtasks, for
+			// rolling up the time spent on subtasks
+			Db.Exec(`
+				create view if not exists task_tree(ancestor_id, task_id) as
+				with recursive tree(ancestor_id, task_id) as (
+					select id, id from task
+					union all
+					select t.parent_id, tree.task_id from tree join task t on t.id = tree.ancestor_id where t.parent_id is not null
+				)
+				select ancestor_id, task_id from tree;
+			`)
+		},
+	},
+	{
+		Version: 10,
+		Up: func() {
+			Db.Exec(`
+				create table if not exists client (
+					id integer primary key,
+					name text
+				);
+			`)
+			Db.Exec(`
+				alter table project add column client_id integer references client(id) on delete set null;
+			`)
+		},
+	},
+	{
+		Version: 11,
+		Up: func() {
+			Db.Exec(`
+				alter table project add column archived boolean default false;
+			`)
+			Db.Exec(`
+				alter table task add column archived boolean default false;
+			`)
+		},
+	},
+	{
+		Version: 12,
+		Up: func() {
+			for _, table := range []string{"project", "task"} {
+				Db.Exec("alter table " + table + " add column description text;")
+				Db.Exec("alter table " + table + " add column color text;")
+				Db.Exec("alter table " + table + " add column ref text;")
+			}
+		},
+	},
 }
 
 func migrateDb() {
@@ -1,8 +1,12 @@
 package db
 
 import (
+	"fmt"
 	"log"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Setting struct {
@@ -11,14 +15,46 @@ type Setting struct {
 }
 
 type Settings struct {
-	SchemaVersion int
+	SchemaVersion  int
+	WorkHours      [7]time.Duration
+	BalanceStart   time.Time
+	FirstDayOfWeek time.Weekday
+	Timezone       string
+	GitTaskPattern string
 }
 
 const (
-	SchemaVersion = "SCHEMA_VERSION"
+	SchemaVersion  = "SCHEMA_VERSION"
+	WorkHours      = "WORK_HOURS"
+	BalanceStart   = "BALANCE_START"
+	FirstDayOfWeek = "FIRST_DAY_OF_WEEK"
+	Timezone       = "TIMEZONE"
+	GitTaskPattern = "GIT_TASK_PATTERN"
 )
 
-var settings Settings
+// Settings which can be changed with the config command, keyed by the name
+// given on the command line
+var ConfigKeys = map[string]string{
+	"work-hours":        WorkHours,
+	"balance-start":     BalanceStart,
+	"first-day-of-week": FirstDayOfWeek,
+	"timezone":          Timezone,
+	"git-task-pattern":  GitTaskPattern,
+}
+
+// The settings used when they haven't been changed. A new database doesn't
+// have a schema version, so all the migrations are run.
+var defaultSettings = Settings{
+	SchemaVersion:  -1,
+	WorkHours:      [7]time.Duration{0, 8 * time.Hour, 8 * time.Hour, 8 * time.Hour, 8 * time.Hour, 8 * time.Hour, 0},
+	FirstDayOfWeek: time.Monday,
+}
+
+var settings = defaultSettings
+
+func GetSettings() Settings {
+	return settings
+}
 
 func updateSetting(key string, value interface{}) {
 	Db.Exec(`
@@ -26,11 +62,47 @@ func updateSetting(key string, value interface{}) {
 	`, key, value)
 }
 
+// Validate and store a setting
+func UpdateSetting(key string, value string) error {
+	s := settings
+	if err := parseSetting(&s, key, value); err != nil {
+		return err
+	}
+	settings = s
+	updateSetting(key, value)
+	return nil
+}
+
+func DeleteSetting(key string) {
+	Db.Exec("delete from setting where key = $1", key)
+}
+
+func GetSetting(key string) (value string) {
+	rows, err := Db.Query("select value from setting where key = $1", key)
+	if err != nil {
+		Fatal(err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		rows.Scan(&value)
+	}
+	return
+}
+
 func getSettings() {
+	settings = defaultSettings
+
+	// The setting table is created by the first migration, which hasn't run
+	// yet for a new database
+	if _, err := Db.Exec("create table if not exists setting (key text primary key, value text)"); err != nil {
+		Fatal(err)
+	}
+
 	query, err := Db.Query("select * from setting")
 	if err != nil {
-		log.Fatal(err)
+		Fatal(err)
 	}
+	defer query.Close()
 
 	for query.Next() {
 		var setting Setting
@@ -38,9 +110,53 @@ func getSettings() {
 			&setting.Key,
 			&setting.Value,
 		)
-		switch setting.Key {
-		case SchemaVersion:
-			settings.SchemaVersion, _ = strconv.Atoi(setting.Value)
+		if err := parseSetting(&settings, setting.Key, setting.Value); err != nil {
+			log.Printf("bad setting %s: %s", setting.Key, err)
+		}
+	}
+}
+
+func parseSetting(s *Settings, key string, value string) (err error) {
+	switch key {
+	case SchemaVersion:
+		s.SchemaVersion, _ = strconv.Atoi(value)
+	case WorkHours:
+		// Hours for each day of the week, starting on Monday (eg. 8h,8h,8h,8h,8h,0,0)
+		days := strings.Split(value, ",")
+		if len(days) != 7 {
+			return fmt.Errorf("expected 7 comma separated durations, starting on Monday")
+		}
+		var workHours [7]time.Duration
+		for i, d := range days {
+			if workHours[(i+1)%7], err = time.ParseDuration(strings.TrimSpace(d)); err != nil {
+				return err
+			}
+		}
+		s.WorkHours = workHours
+	case BalanceStart:
+		if s.BalanceStart, err = time.ParseInLocation("2006-01-02", value, time.Local); err != nil {
+			return err
+		}
+	case FirstDayOfWeek:
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.EqualFold(value, d.String()) {
+				s.FirstDayOfWeek = d
+				return nil
+			}
+		}
+		return fmt.Errorf("expected a day of the week (eg. monday)")
+	case Timezone:
+		if _, err := time.LoadLocation(value); err != nil {
+			return err
+		}
+		s.Timezone = value
+	case GitTaskPattern:
+		// Regexp for finding the task in a branch name, using the first
+		// group if there is one (eg. [A-Z]+-[0-9]+ for an issue key)
+		if _, err := regexp.Compile(value); err != nil {
+			return err
 		}
+		s.GitTaskPattern = value
 	}
+	return nil
 }
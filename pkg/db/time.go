@@ -0,0 +1,54 @@
+package db
+
+import "time"
+
+// FormatTime formats a time for storing in the database. Times are always
+// stored in UTC so they can be compared regardless of the offset they were
+// recorded in.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ParseTime parses a time stored in the database into the display timezone.
+// An empty or invalid value returns the zero time.
+func ParseTime(v string) time.Time {
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.Local()
+}
+
+// SetTimezone changes the timezone used for displaying times and for
+// splitting frames into days
+func SetTimezone(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+	time.Local = loc
+
+	// Dates in the settings are parsed in the local timezone, so parse them
+	// again if they've already been read
+	if Db != nil {
+		getSettings()
+	}
+	return nil
+}
+
+// Parse the date part of a date or datetime value (eg. 2026-10-19 00:00:00)
+func parseLocalDate(v string) time.Time {
+	if len(v) > len("2006-01-02") {
+		v = v[:len("2006-01-02")]
+	}
+	t, _ := time.ParseInLocation("2006-01-02", v, time.Local)
+	return t
+}
+
+func localDayStart(date string) int64 {
+	return parseLocalDate(date).Unix()
+}
+
+func localDayEnd(date string) int64 {
+	return parseLocalDate(date).AddDate(0, 0, 1).Unix()
+}
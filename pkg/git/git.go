@@ -0,0 +1,106 @@
+// Package git reads repositories by running the git command
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Run git in the directory, returning its trimmed output
+func run(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// TopLevel returns the root of the working tree containing dir
+func TopLevel(dir string) (string, error) {
+	return run(dir, "rev-parse", "--show-toplevel")
+}
+
+// Branch returns the name of the branch checked out in dir, or an error if the
+// HEAD is detached
+func Branch(dir string) (string, error) {
+	branch, err := run(dir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("HEAD is detached")
+	}
+	return branch, nil
+}
+
+// Config returns the value of a git config key for the repository in dir, or
+// an empty string if it isn't set
+func Config(dir, key string) string {
+	value, _ := run(dir, "config", "--get", key)
+	return value
+}
+
+// HooksDir returns the directory git runs hooks from for the repository in
+// dir, which respects core.hooksPath and worktrees
+func HooksDir(dir string) (string, error) {
+	path, err := run(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
+
+type Commit struct {
+	Hash    string
+	Time    time.Time
+	Subject string
+}
+
+// Commits returns the commits on any branch by the author since a time,
+// ordered by the time they were authored. The author is matched as a fixed
+// string rather than a pattern.
+func Commits(dir, author string, since time.Time) ([]*Commit, error) {
+	// git log's --since filters on the commit time, which may be after the
+	// author time for rebased commits, so commits are filtered on the
+	// author time below
+	args := []string{"log", "--all", "--no-merges", "--fixed-strings", "--author=" + author, "--format=%h%x1f%at%x1f%s"}
+	out, err := run(dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*Commit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		at, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		t := time.Unix(at, 0)
+		if t.Before(since) {
+			continue
+		}
+		commits = append(commits, &Commit{
+			Hash:    fields[0],
+			Time:    t,
+			Subject: fields[2],
+		})
+	}
+	sort.Slice(commits, func(i, j int) bool { return commits[i].Time.Before(commits[j].Time) })
+	return commits, nil
+}
@@ -0,0 +1,124 @@
+// Package hooks runs user scripts from the hooks directory in the XDG config
+// directory (eg. ~/.config/track-cli/hooks/post-start) when frames change
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/jasonwoodland/track/pkg/model"
+)
+
+// Hooks which can be installed. A non-zero exit from a pre-* hook aborts the
+// command.
+const (
+	PreStart   = "pre-start"
+	PostStart  = "post-start"
+	PostStop   = "post-stop"
+	PostCancel = "post-cancel"
+	PostEdit   = "post-edit"
+)
+
+// Actions describing how a frame was changed, for post-edit hooks
+const (
+	ActionStart  = "start"
+	ActionStop   = "stop"
+	ActionCancel = "cancel"
+	ActionAdd    = "add"
+	ActionEdit   = "edit"
+	ActionMove   = "move"
+	ActionRemove = "remove"
+)
+
+// Event is passed to hooks as JSON on stdin
+type Event struct {
+	Hook      string     `json:"hook"`
+	Action    string     `json:"action"`
+	Project   string     `json:"project"`
+	Task      string     `json:"task"`
+	FrameId   int64      `json:"frame_id,omitempty"`
+	StartTime time.Time  `json:"start_time"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	Note      string     `json:"note,omitempty"`
+}
+
+// Where the output of hooks is written. Commands which draw to the whole
+// screen (eg. ui) discard it.
+var (
+	Stdout io.Writer = os.Stdout
+	Stderr io.Writer = os.Stderr
+)
+
+// How long a hook can run before it's killed
+const timeout = 30 * time.Second
+
+// Dir returns the directory hooks are installed in
+func Dir() string {
+	return filepath.Join(xdg.ConfigHome, "track-cli", "hooks")
+}
+
+// Run runs the hook for a change to the frame, if the hook is installed.
+// Frames which haven't been added yet (eg. for pre-start) have no id. Returns
+// an error if the hook exits with a non-zero status or doesn't finish within
+// the timeout.
+func Run(hook, action string, f *model.Frame) error {
+	path := filepath.Join(Dir(), hook)
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return nil
+	}
+
+	e := Event{
+		Hook:      hook,
+		Action:    action,
+		Project:   f.Task.Project.Name,
+		Task:      f.Task.Name,
+		FrameId:   f.Id,
+		StartTime: f.StartTime,
+		Note:      f.Note,
+	}
+	if !f.IsRunning() {
+		e.EndTime = &f.EndTime
+	}
+
+	stdin, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = Stdout
+	cmd.Stderr = Stderr
+	cmd.Env = append(
+		os.Environ(),
+		"TRACK_HOOK="+e.Hook,
+		"TRACK_ACTION="+e.Action,
+		"TRACK_PROJECT="+e.Project,
+		"TRACK_TASK="+e.Task,
+		"TRACK_FRAME_ID="+strconv.FormatInt(e.FrameId, 10),
+		"TRACK_START_TIME="+e.StartTime.Format(time.RFC3339),
+		"TRACK_NOTE="+e.Note,
+	)
+	if e.EndTime != nil {
+		cmd.Env = append(cmd.Env, "TRACK_END_TIME="+e.EndTime.Format(time.RFC3339))
+	}
+
+	if err := cmd.Run(); ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%s: timed out after %s", hook, timeout)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", hook, err)
+	}
+	return nil
+}
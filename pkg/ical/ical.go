@@ -0,0 +1,213 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type Event struct {
+	UID          string
+	RecurrenceID string
+	Summary      string
+	Description  string
+	Categories   []string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VEVENTs from an iCalendar (RFC 5545) file
+func Parse(r io.Reader) (events []*Event, err error) {
+	var (
+		lines []string
+		event *Event
+	)
+
+	// Unfold lines which were split over multiple lines
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+
+		switch {
+		case p.name == "BEGIN" && p.value == "VEVENT":
+			event = &Event{}
+		case p.name == "END" && p.value == "VEVENT":
+			if event != nil {
+				if event.End.IsZero() {
+					if event.AllDay {
+						event.End = event.Start.AddDate(0, 0, 1)
+					} else {
+						event.End = event.Start
+					}
+				}
+				events = append(events, event)
+			}
+			event = nil
+		case event == nil:
+			continue
+		case p.name == "UID":
+			event.UID = p.value
+		case p.name == "RECURRENCE-ID":
+			event.RecurrenceID = p.value
+		case p.name == "SUMMARY":
+			event.Summary = unescape(p.value)
+		case p.name == "DESCRIPTION":
+			event.Description = unescape(p.value)
+		case p.name == "CATEGORIES":
+			for _, c := range splitValues(p.value) {
+				event.Categories = append(event.Categories, unescape(c))
+			}
+		case p.name == "DTSTART":
+			if event.Start, event.AllDay, err = parseTime(p); err != nil {
+				return nil, fmt.Errorf("line %d: %s", i+1, err)
+			}
+		case p.name == "DTEND":
+			if event.End, _, err = parseTime(p); err != nil {
+				return nil, fmt.Errorf("line %d: %s", i+1, err)
+			}
+		}
+	}
+	return
+}
+
+func parseProperty(line string) (p property, err error) {
+	// Find the colon separating the name and parameters from the value,
+	// ignoring any colons inside quoted parameter values
+	quoted := false
+	sep := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			sep = i
+			break
+		}
+	}
+	if sep == -1 {
+		return p, fmt.Errorf("bad content line: %s", line)
+	}
+
+	p.value = line[sep+1:]
+	p.params = make(map[string]string)
+	parts := strings.Split(line[:sep], ";")
+	p.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if kv := strings.SplitN(param, "=", 2); len(kv) == 2 {
+			p.params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return
+}
+
+func parseTime(p property) (t time.Time, allDay bool, err error) {
+	if p.params["VALUE"] == "DATE" || len(p.value) == len("20060102") {
+		t, err = time.ParseInLocation("20060102", p.value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(p.value, "Z") {
+		t, err = time.Parse("20060102T150405Z", p.value)
+		return t, false, err
+	}
+	loc := time.Local
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err = time.ParseInLocation("20060102T150405", p.value, loc)
+	return t, false, err
+}
+
+// Write writes the events as an iCalendar file, with times in UTC
+func Write(w io.Writer, events []*Event) error {
+	bw := bufio.NewWriter(w)
+	writeLine := func(line string) {
+		// Lines longer than 75 octets are folded onto continuation lines
+		// starting with a space, without splitting UTF-8 characters
+		for len(line) > 75 {
+			i := 75
+			for i > 0 && !utf8.RuneStart(line[i]) {
+				i--
+			}
+			bw.WriteString(line[:i] + "\r\n")
+			line = " " + line[i:]
+		}
+		bw.WriteString(line + "\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//track//track-cli//EN")
+	now := time.Now().UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		writeLine("BEGIN:VEVENT")
+		writeLine("UID:" + e.UID)
+		writeLine("DTSTAMP:" + now)
+		writeLine("DTSTART:" + e.Start.UTC().Format("20060102T150405Z"))
+		writeLine("DTEND:" + e.End.UTC().Format("20060102T150405Z"))
+		writeLine("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			writeLine("DESCRIPTION:" + escape(e.Description))
+		}
+		if len(e.Categories) != 0 {
+			var categories []string
+			for _, c := range e.Categories {
+				categories = append(categories, escape(c))
+			}
+			writeLine("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		writeLine("END:VEVENT")
+	}
+	writeLine("END:VCALENDAR")
+	return bw.Flush()
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`).Replace(s)
+}
+
+// Split a list of values on the commas which aren't escaped
+func splitValues(s string) (values []string) {
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+		} else if s[i] == ',' {
+			values = append(values, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(values, s[start:])
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/db"
+)
+
+var AbsenceTypes = []string{"vacation", "sick", "holiday", "other"}
+
+type Absence struct {
+	Id          int64
+	Type        string
+	StartDate   time.Time
+	EndDate     time.Time
+	Description string
+}
+
+// IsLeave is true for holidays and leave, which don't count towards the
+// expected work hours. Other absences are still expected to be made up.
+func (a *Absence) IsLeave() bool {
+	return a.Type != "other"
+}
+
+// GetAbsences returns all absences which overlap with the from and to dates
+func GetAbsences(from, to time.Time) (absences []*Absence) {
+	rows, err := db.Db.Query(`
+		select id, type, start_date, end_date, coalesce(description, '')
+		from absence
+		where end_date >= $1 and start_date <= $2
+		order by start_date
+	`, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		a := &Absence{}
+		var startDate, endDate string
+		rows.Scan(&a.Id, &a.Type, &startDate, &endDate, &a.Description)
+		a.StartDate, _ = time.ParseInLocation("2006-01-02", startDate, time.Local)
+		a.EndDate, _ = time.ParseInLocation("2006-01-02", endDate, time.Local)
+		absences = append(absences, a)
+	}
+	return
+}
+
+// GetAbsenceDays returns the absences between from and to, keyed by each date
+// (eg. 2026-12-24) they cover
+func GetAbsenceDays(from, to time.Time) map[string]*Absence {
+	days := make(map[string]*Absence)
+	for _, a := range GetAbsences(from, to) {
+		for d := a.StartDate; !d.After(a.EndDate); d = d.AddDate(0, 0, 1) {
+			days[d.Format("2006-01-02")] = a
+		}
+	}
+	return days
+}
+
+func GetAbsenceById(id int64) (a *Absence) {
+	rows, err := db.Db.Query("select type, start_date, end_date, coalesce(description, '') from absence where id = $1", id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		a = &Absence{
+			Id: id,
+		}
+		var startDate, endDate string
+		rows.Scan(&a.Type, &startDate, &endDate, &a.Description)
+		a.StartDate, _ = time.ParseInLocation("2006-01-02", startDate, time.Local)
+		a.EndDate, _ = time.ParseInLocation("2006-01-02", endDate, time.Local)
+	}
+	return
+}
+
+func AddAbsence(absenceType string, startDate, endDate time.Time, description string) *Absence {
+	res, err := db.Db.Exec(
+		"insert into absence (type, start_date, end_date, description) values ($1, $2, $3, $4)",
+		absenceType,
+		startDate.Format("2006-01-02"),
+		endDate.Format("2006-01-02"),
+		description,
+	)
+	if err != nil {
+		db.Fatal(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		db.Fatal(err)
+	}
+	return &Absence{
+		Id:          id,
+		Type:        absenceType,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Description: description,
+	}
+}
+
+func (a *Absence) Remove() {
+	_, err := db.Db.Exec("delete from absence where id = $1", a.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+// Number of days covered by the absence
+func (a *Absence) GetNumDays() int {
+	return int(a.EndDate.Sub(a.StartDate).Hours()/24+0.5) + 1
+}
@@ -0,0 +1,53 @@
+package model
+
+import "github.com/jasonwoodland/track/pkg/db"
+
+// SetArchived archives the project, hiding it from the list of projects,
+// completion and start. Its frames are still included in logs and reports.
+func (p *Project) SetArchived(archived bool) {
+	_, err := db.Db.Exec("update project set archived = $1 where id = $2", archived, p.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	p.Archived = archived
+}
+
+// SetArchived archives the task and its subtasks, like Project.SetArchived.
+// Unarchiving a task also unarchives its parents, so it isn't hidden by them.
+func (t *Task) SetArchived(archived bool) {
+	query := "update task set archived = $1 where id in (select task_id from task_tree where ancestor_id = $2)"
+	if !archived {
+		query = `
+			update task set archived = $1
+			where
+				id in (select task_id from task_tree where ancestor_id = $2)
+			or
+				id in (select ancestor_id from task_tree where task_id = $2)
+		`
+	}
+	_, err := db.Db.Exec(query, archived, t.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	t.Archived = archived
+}
+
+// ActiveProjects returns the projects which aren't archived
+func ActiveProjects(projects []*Project) (active []*Project) {
+	for _, p := range projects {
+		if !p.Archived {
+			active = append(active, p)
+		}
+	}
+	return
+}
+
+// ActiveTasks returns the tasks which aren't archived
+func ActiveTasks(tasks []*Task) (active []*Task) {
+	for _, t := range tasks {
+		if !t.Archived {
+			active = append(active, t)
+		}
+	}
+	return
+}
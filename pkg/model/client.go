@@ -0,0 +1,138 @@
+package model
+
+import (
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/util"
+)
+
+// Client is who several projects are billed to
+type Client struct {
+	Id   int64
+	Name string
+}
+
+func GetClients() (clients []*Client) {
+	rows, err := db.Db.Query("select id, name from client order by name")
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		c := &Client{}
+		rows.Scan(&c.Id, &c.Name)
+		clients = append(clients, c)
+	}
+	return
+}
+
+func GetClientById(id int64) (c *Client) {
+	rows, err := db.Db.Query("select name from client where id = $1", id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		c = &Client{
+			Id: id,
+		}
+		rows.Scan(&c.Name)
+	}
+	return
+}
+
+func GetClientByName(name string) (c *Client) {
+	rows, err := db.Db.Query("select id from client where name = $1", name)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		c = &Client{
+			Name: name,
+		}
+		rows.Scan(&c.Id)
+	}
+	return
+}
+
+func AddClient(name string) *Client {
+	res, err := db.Db.Exec("insert into client (name) values ($1)", name)
+	if err != nil {
+		db.Fatal(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		db.Fatal(err)
+	}
+	return &Client{
+		Id:   id,
+		Name: name,
+	}
+}
+
+func (c *Client) Rename(name string) {
+	_, err := db.Db.Exec("update client set name = $1 where id = $2", name, c.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	c.Name = name
+}
+
+// Remove deletes the client, keeping its projects without a client
+func (c *Client) Remove() {
+	_, err := db.Db.Exec("delete from client where id = $1", c.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+// GetProjects returns the projects billed to the client
+func (c *Client) GetProjects() (projects []*Project) {
+	for _, p := range GetProjects() {
+		if p.ClientId == c.Id {
+			projects = append(projects, p)
+		}
+	}
+	return
+}
+
+// FindClients returns the clients which best match name, which may be a
+// prefix or fuzzy match of the client name (see util.Match)
+func FindClients(name string) (clients []*Client) {
+	all := GetClients()
+	for _, i := range util.Match(name, clientNames(all)) {
+		clients = append(clients, all[i])
+	}
+	return
+}
+
+// SimilarClients returns the clients with names a few typos away from name
+func SimilarClients(name string) (clients []*Client) {
+	all := GetClients()
+	for _, i := range util.Similar(name, clientNames(all)) {
+		clients = append(clients, all[i])
+	}
+	return
+}
+
+func clientNames(clients []*Client) (names []string) {
+	for _, c := range clients {
+		names = append(names, c.Name)
+	}
+	return
+}
+
+// SetClient sets the client the project is billed to, or removes it if c is
+// nil
+func (p *Project) SetClient(c *Client) {
+	var value interface{}
+	p.ClientId = 0
+	if c != nil {
+		value = c.Id
+		p.ClientId = c.Id
+	}
+	_, err := db.Db.Exec("update project set client_id = $1 where id = $2", value, p.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
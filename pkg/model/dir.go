@@ -0,0 +1,89 @@
+package model
+
+import (
+	"bufio"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jasonwoodland/track/pkg/db"
+)
+
+// ProjectFile is the name of the file which sets the project for the
+// directory it's in and its subdirectories
+const ProjectFile = ".track"
+
+// GetProjectNameForDir returns the name of the project for the directory,
+// from the nearest .track file or directory set with project set --dir, or
+// an empty string if the directory isn't mapped to a project
+func GetProjectNameForDir(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	if d, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = d
+	}
+
+	for {
+		if name := readProjectFile(filepath.Join(dir, ProjectFile)); name != "" {
+			return name
+		}
+
+		var name string
+		err := db.Db.QueryRow("select name from project where dir = $1", dir).Scan(&name)
+		if err == nil {
+			return name
+		} else if err != sql.ErrNoRows {
+			db.Fatal(err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// The project name is the first line of the file which isn't empty or a
+// comment
+func readProjectFile(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+// SetDir maps the directory and its subdirectories to the project, or
+// removes the mapping if dir is empty. Returns the absolute path stored.
+func (p *Project) SetDir(dir string) (string, error) {
+	var value interface{}
+	if dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", err
+		}
+		if abs, err = filepath.EvalSymlinks(abs); err != nil {
+			return "", err
+		}
+		dir = abs
+		value = abs
+	}
+	_, err := db.Db.Exec("update project set dir = $1 where id = $2", value, p.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	return dir, nil
+}
@@ -1,10 +1,237 @@
 package model
 
-import "time"
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/db"
+)
 
 type Frame struct {
 	Id        int64
 	Task      *Task
 	StartTime time.Time
 	EndTime   time.Time
+	Note      string
+}
+
+// GetFrames returns all frames which overlap with the from and to times,
+// ordered by their start time. Running frames have a zero EndTime.
+func GetFrames(from, to time.Time) []*Frame {
+	return GetFramesMatching(from, to, "", "")
+}
+
+// GetFramesMatching returns the frames like GetFrames, on the projects and
+// task paths which contain projectName and taskName like the log and report
+// filters
+func GetFramesMatching(from, to time.Time, projectName, taskName string) (frames []*Frame) {
+	rows, err := db.Db.Query(`
+		select f.id, f.task_id, f.start_time, coalesce(f.end_time, ''), coalesce(f.note, '')
+		from frame f
+		join task t on t.id = f.task_id
+		join task_path tp on tp.id = t.id
+		join project p on p.id = t.project_id
+		where
+			f.start_time < $1
+		and
+			(f.end_time is null or f.end_time > $2)
+		and
+			p.name like $3
+		and
+			tp.path like $4
+		order by f.start_time
+	`, db.FormatTime(to), db.FormatTime(from), "%"+projectName+"%", "%"+taskName+"%")
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+
+	tasks := make(map[int64]*Task)
+	for rows.Next() {
+		f := &Frame{}
+		var taskId int64
+		var startTime, endTime string
+		rows.Scan(&f.Id, &taskId, &startTime, &endTime, &f.Note)
+		f.StartTime = db.ParseTime(startTime)
+		f.EndTime = db.ParseTime(endTime)
+		frames = append(frames, f)
+
+		if tasks[taskId] == nil {
+			tasks[taskId] = &Task{Id: taskId}
+		}
+		f.Task = tasks[taskId]
+	}
+	rows.Close()
+
+	for _, t := range tasks {
+		*t = GetTaskById(t.Id)
+	}
+	return
+}
+
+func GetFrameById(id int64) (f *Frame) {
+	rows, err := db.Db.Query("select task_id, start_time, coalesce(end_time, ''), coalesce(note, '') from frame where id = $1", id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		f = &Frame{
+			Id: id,
+		}
+		var taskId int64
+		var startTime, endTime string
+		rows.Scan(&taskId, &startTime, &endTime, &f.Note)
+		f.StartTime = db.ParseTime(startTime)
+		f.EndTime = db.ParseTime(endTime)
+		rows.Close()
+		t := GetTaskById(taskId)
+		f.Task = &t
+	}
+	return
+}
+
+// GetRunningFrame returns the frame which hasn't been stopped, or nil if
+// nothing is running
+func GetRunningFrame() *Frame {
+	var id int64
+	err := db.Db.QueryRow("select id from frame where end_time is null").Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		db.Fatal(err)
+	}
+	return GetFrameById(id)
+}
+
+// StartFrame starts tracking time on the task
+func StartFrame(t *Task, startTime time.Time) *Frame {
+	res, err := db.Db.Exec(
+		"insert into frame (task_id, start_time) values ($1, $2)",
+		t.Id,
+		db.FormatTime(startTime),
+	)
+	if err != nil {
+		db.Fatal(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		db.Fatal(err)
+	}
+	return &Frame{
+		Id:        id,
+		Task:      t,
+		StartTime: startTime,
+	}
+}
+
+// AddFrame adds a stopped frame to the task
+func AddFrame(t *Task, startTime, endTime time.Time) *Frame {
+	res, err := db.Db.Exec(
+		"insert into frame (task_id, start_time, end_time) values ($1, $2, $3)",
+		t.Id,
+		db.FormatTime(startTime),
+		db.FormatTime(endTime),
+	)
+	if err != nil {
+		db.Fatal(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		db.Fatal(err)
+	}
+	return &Frame{
+		Id:        id,
+		Task:      t,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+}
+
+// StopFrame stops the running frame, returning false if nothing was running
+func StopFrame(endTime time.Time) bool {
+	res, err := db.Db.Exec(
+		"update frame set end_time = $1 where end_time is null",
+		db.FormatTime(endTime),
+	)
+	if err != nil {
+		db.Fatal(err)
+	}
+	n, _ := res.RowsAffected()
+	return n != 0
+}
+
+// IsRunning is true if the frame hasn't been stopped
+func (f *Frame) IsRunning() bool {
+	return f.EndTime.IsZero()
+}
+
+// DurationBetween returns the part of the frame's duration which falls
+// between from and to, treating running frames as ending now
+func (f *Frame) DurationBetween(from, to time.Time) time.Duration {
+	start, end := f.StartTime, f.EndTime
+	if f.IsRunning() {
+		end = time.Now()
+	}
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// Update saves the frame's task, times and note
+func (f *Frame) Update() {
+	var endTime interface{}
+	if !f.IsRunning() {
+		endTime = db.FormatTime(f.EndTime)
+	}
+	var note interface{}
+	if f.Note != "" {
+		note = f.Note
+	}
+	_, err := db.Db.Exec(
+		"update frame set task_id = $1, start_time = $2, end_time = $3, note = $4 where id = $5",
+		f.Task.Id,
+		db.FormatTime(f.StartTime),
+		endTime,
+		note,
+		f.Id,
+	)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+// GetFrameByUid returns the frame imported from the calendar event with the
+// UID, or nil if it hasn't been imported
+func GetFrameByUid(uid string) *Frame {
+	var id int64
+	err := db.Db.QueryRow("select id from frame where uid = $1", uid).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		db.Fatal(err)
+	}
+	return GetFrameById(id)
+}
+
+// SetUid records the UID of the calendar event the frame was imported from
+func (f *Frame) SetUid(uid string) {
+	_, err := db.Db.Exec("update frame set uid = $1 where id = $2", uid, f.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+func (f *Frame) Remove() {
+	_, err := db.Db.Exec("delete from frame where id = $1", f.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
 }
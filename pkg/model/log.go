@@ -0,0 +1,107 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/db"
+)
+
+// LogEntry is the time spent on a task within a range of time
+type LogEntry struct {
+	Task            *Task
+	StartTime       time.Time
+	EndTime         time.Time
+	Duration        time.Duration
+	ProjectDuration time.Duration
+}
+
+// GetLog returns the time spent on each task between from and to, ordered by
+// project name and start time. Frames are clipped to the range, so frames
+// which only partially fall within the range only count the time inside it.
+// Tasks can be filtered by project and task names containing the given
+// strings.
+func GetLog(from, to time.Time, projectName, taskName string) (entries []*LogEntry) {
+	query := `
+		with clipped(task_id, start_time, end_time, seconds) as (
+			select
+				f.task_id,
+				f.start_time,
+				f.end_time,
+				` + db.ClippedSeconds("f", "?1", "?2") + `
+			from frame f
+			where ` + db.Overlaps("f", "?1", "?2") + `
+		)
+		select
+			t.id,
+			min(c.start_time) as start_date,
+			coalesce(max(c.end_time), '') as end_date,
+			sum(c.seconds) as task_total,
+			(
+				select
+					sum(c2.seconds)
+				from clipped c2
+				left join task t2 on t2.id = c2.task_id
+				where
+					t2.project_id = p.id
+			) as project_total
+		from clipped c
+		join task t on t.id = c.task_id
+		join task_path tp on tp.id = t.id
+		join project p on p.id = t.project_id
+	`
+
+	params := []interface{}{
+		from.Unix(),
+		to.Unix(),
+	}
+
+	var whereConds []string
+
+	if projectName != "" {
+		whereConds = append(whereConds, "p.name like ?")
+		params = append(params, "%"+projectName+"%")
+	}
+
+	if taskName != "" {
+		whereConds = append(whereConds, "tp.path like ?")
+		params = append(params, "%"+taskName+"%")
+	}
+
+	// Add where conditions to query
+	if len(whereConds) != 0 {
+		query += "where\n" + strings.Join(whereConds, "\nand\n")
+	}
+
+	query += `
+		group by c.task_id
+		order by p.name, start_date
+	`
+
+	rows, err := db.Db.Query(query, params...)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+
+	var taskIds []int64
+	for rows.Next() {
+		e := &LogEntry{}
+		var taskId int64
+		var startTime, endTime string
+		rows.Scan(&taskId, &startTime, &endTime, &e.Duration, &e.ProjectDuration)
+		e.StartTime = db.ParseTime(startTime)
+		e.EndTime = db.ParseTime(endTime)
+		e.Duration *= time.Second
+		e.ProjectDuration *= time.Second
+		entries = append(entries, e)
+		taskIds = append(taskIds, taskId)
+	}
+	rows.Close()
+
+	for i, e := range entries {
+		t := GetTaskById(taskIds[i])
+		e.Task = &t
+	}
+	return
+}
@@ -0,0 +1,62 @@
+package model
+
+import "github.com/jasonwoodland/track/pkg/db"
+
+// Metadata is a project or task, which both have a description, color and
+// external reference
+type Metadata interface {
+	SetDescription(description string)
+	SetColor(color string)
+	SetRef(ref string)
+}
+
+// Set a text column of a project or task, or clear it if value is empty
+func setMetadata(table, column string, id int64, value string) {
+	var v interface{}
+	if value != "" {
+		v = value
+	}
+	_, err := db.Db.Exec("update "+table+" set "+column+" = $1 where id = $2", v, id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+func (p *Project) SetDescription(description string) {
+	setMetadata("project", "description", p.Id, description)
+	p.Description = description
+}
+
+func (p *Project) SetColor(color string) {
+	setMetadata("project", "color", p.Id, color)
+	p.Color = color
+}
+
+func (p *Project) SetRef(ref string) {
+	setMetadata("project", "ref", p.Id, ref)
+	p.Ref = ref
+}
+
+func (t *Task) SetDescription(description string) {
+	setMetadata("task", "description", t.Id, description)
+	t.Description = description
+}
+
+func (t *Task) SetColor(color string) {
+	setMetadata("task", "color", t.Id, color)
+	t.Color = color
+}
+
+func (t *Task) SetRef(ref string) {
+	setMetadata("task", "ref", t.Id, ref)
+	t.Ref = ref
+}
+
+// GetRef returns the task's external reference, or its project's if it
+// doesn't have one
+func (t *Task) GetRef() string {
+	if t.Ref != "" {
+		return t.Ref
+	}
+	return t.Project.Ref
+}
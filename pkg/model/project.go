@@ -1,104 +1,334 @@
 package model
 
 import (
-	"log"
+	"strings"
+	"time"
 
 	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/util"
 )
 
 type Project struct {
-	Id   int64
-	Name string
+	Id       int64
+	Name     string
+	Budget   time.Duration
+	ClientId int64
+	Archived bool
+
+	// Metadata shown alongside the project. Color is the name of the color
+	// tag it's rendered in, and Ref is an external reference such as a
+	// purchase order number.
+	Description string
+	Color       string
+	Ref         string
 }
 
 func GetProjects() (projects []*Project) {
-	rows, err := db.Db.Query("select id, name from project")
+	rows, err := db.Db.Query("select id, name, coalesce(budget, 0), coalesce(client_id, 0), coalesce(archived, false), coalesce(description, ''), coalesce(color, ''), coalesce(ref, '') from project")
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		p := &Project{}
-		rows.Scan(&p.Id, &p.Name)
+		rows.Scan(&p.Id, &p.Name, &p.Budget, &p.ClientId, &p.Archived, &p.Description, &p.Color, &p.Ref)
+		p.Budget *= time.Second
 		projects = append(projects, p)
 	}
 	return
 }
 
 func GetProjectById(id int64) (p *Project) {
-	rows, err := db.Db.Query("select name from project where id = $1", id)
+	rows, err := db.Db.Query("select name, coalesce(budget, 0), coalesce(client_id, 0), coalesce(archived, false), coalesce(description, ''), coalesce(color, ''), coalesce(ref, '') from project where id = $1", id)
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	if rows.Next() {
 		p = &Project{
 			Id: id,
 		}
-		rows.Scan(&p.Name)
+		rows.Scan(&p.Name, &p.Budget, &p.ClientId, &p.Archived, &p.Description, &p.Color, &p.Ref)
+		p.Budget *= time.Second
 	}
 	return
 }
 
 func GetProjectByName(name string) (p *Project) {
-	rows, err := db.Db.Query("select id from project where name = $1", name)
+	rows, err := db.Db.Query("select id, coalesce(budget, 0), coalesce(client_id, 0), coalesce(archived, false), coalesce(description, ''), coalesce(color, ''), coalesce(ref, '') from project where name = $1", name)
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	if rows.Next() {
 		p = &Project{
 			Name: name,
 		}
-		rows.Scan(&p.Id)
+		rows.Scan(&p.Id, &p.Budget, &p.ClientId, &p.Archived, &p.Description, &p.Color, &p.Ref)
+		p.Budget *= time.Second
 	}
 	return
 }
 
-func (p *Project) GetTask(name string) (t *Task) {
-	rows, err := db.Db.Query("select id from task where project_id = $1 and name = $2", p.Id, name)
+func AddProject(name string) *Project {
+	res, err := db.Db.Exec("insert into project (name) values ($1)", name)
+	if err != nil {
+		db.Fatal(err)
+	}
+	id, err := res.LastInsertId()
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
+	}
+	return &Project{
+		Id:   id,
+		Name: name,
+	}
+}
+
+func (p *Project) Rename(name string) {
+	_, err := db.Db.Exec("update project set name = $1 where id = $2", name, p.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	p.Name = name
+}
+
+func (p *Project) Remove() {
+	_, err := db.Db.Exec("delete from project where id = $1", p.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+// FindProjects returns the projects which best match name, which may be a
+// prefix or fuzzy match of the project name (see util.Match)
+func FindProjects(name string) (projects []*Project) {
+	all := GetProjects()
+	for _, i := range util.Match(name, projectNames(all)) {
+		projects = append(projects, all[i])
+	}
+	return
+}
+
+// SimilarProjects returns the projects with names a few typos away from name
+func SimilarProjects(name string) (projects []*Project) {
+	all := GetProjects()
+	for _, i := range util.Similar(name, projectNames(all)) {
+		projects = append(projects, all[i])
+	}
+	return
+}
+
+func projectNames(projects []*Project) (names []string) {
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	return
+}
+
+// GetTask returns the task with the path (eg. frontend/login/oauth), or nil
+func (p *Project) GetTask(path string) (t *Task) {
+	return p.getTask(db.Db, path)
+}
+
+func (p *Project) getTask(q db.Querier, path string) (t *Task) {
+	rows, err := q.Query(`
+		select t.id, coalesce(t.parent_id, 0), coalesce(t.estimate, 0), coalesce(t.archived, false),
+			coalesce(t.description, ''), coalesce(t.color, ''), coalesce(t.ref, '')
+		from task t
+		join task_path tp on tp.id = t.id
+		where t.project_id = $1 and tp.path = $2
+	`, p.Id, path)
+	if err != nil {
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	if rows.Next() {
 		t = &Task{
-			Name:    name,
+			Name:    path,
 			Project: p,
 		}
-		rows.Scan(&t.Id)
+		rows.Scan(&t.Id, &t.ParentId, &t.Estimate, &t.Archived, &t.Description, &t.Color, &t.Ref)
+		t.Estimate *= time.Second
 	}
 	return
 }
 
+// GetTasks returns the tasks and subtasks on the project, named by their paths
 func (p *Project) GetTasks() (tasks []*Task) {
-	rows, err := db.Db.Query("select id, name from task where project_id = $1", p.Id)
+	rows, err := db.Db.Query(`
+		select t.id, tp.path, coalesce(t.parent_id, 0), coalesce(t.estimate, 0), coalesce(t.archived, false),
+			coalesce(t.description, ''), coalesce(t.color, ''), coalesce(t.ref, '')
+		from task t
+		join task_path tp on tp.id = t.id
+		where t.project_id = $1
+		order by t.id
+	`, p.Id)
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
 		t := &Task{
 			Project: p,
 		}
-		rows.Scan(&t.Id, &t.Name)
+		rows.Scan(&t.Id, &t.Name, &t.ParentId, &t.Estimate, &t.Archived, &t.Description, &t.Color, &t.Ref)
+		t.Estimate *= time.Second
 		tasks = append(tasks, t)
 	}
 	return
 }
 
-func (p *Project) AddTask(name string) *Task {
-	res, err := db.Db.Exec("insert into task (name, project_id) values ($1, $2)", name, p.Id)
+// FindTasks returns the tasks on the project which best match name, which
+// may be a prefix or fuzzy match of the task name (see util.Match)
+func (p *Project) FindTasks(name string) (tasks []*Task) {
+	all := p.GetTasks()
+	for _, i := range util.Match(name, taskNames(all)) {
+		tasks = append(tasks, all[i])
+	}
+	return
+}
+
+// FindTasksByPrefix returns the tasks on the project which best match name,
+// like FindTasks but without fuzzy matches, for commands which add a task if
+// none match (see util.MatchPrefix)
+func (p *Project) FindTasksByPrefix(name string) (tasks []*Task) {
+	all := p.GetTasks()
+	for _, i := range util.MatchPrefix(name, taskNames(all)) {
+		tasks = append(tasks, all[i])
+	}
+	return
+}
+
+// SimilarTasks returns the tasks on the project with names a few typos away
+// from name
+func (p *Project) SimilarTasks(name string) (tasks []*Task) {
+	all := p.GetTasks()
+	for _, i := range util.Similar(name, taskNames(all)) {
+		tasks = append(tasks, all[i])
+	}
+	return
+}
+
+func taskNames(tasks []*Task) (names []string) {
+	for _, t := range tasks {
+		names = append(names, t.Name)
+	}
+	return
+}
+
+// AddTask adds a task with the path (eg. frontend/login/oauth), adding any
+// parent tasks which don't exist yet
+func (p *Project) AddTask(path string) *Task {
+	return p.addTaskPath(db.Db, path)
+}
+
+func (p *Project) addTaskPath(q db.Querier, path string) *Task {
+	var parent *Task
+	segments := splitTaskPath(path)
+	for i := range segments[:len(segments)-1] {
+		parentPath := strings.Join(segments[:i+1], "/")
+		if t := p.getTask(q, parentPath); t != nil {
+			parent = t
+		} else {
+			parent = p.addTask(q, parent, segments[i])
+		}
+	}
+	return p.addTask(q, parent, segments[len(segments)-1])
+}
+
+func (p *Project) addTask(q db.Querier, parent *Task, name string) *Task {
+	var parentId interface{}
+	path := name
+	if parent != nil {
+		parentId = parent.Id
+		path = parent.Name + "/" + name
+	}
+	res, err := q.Exec("insert into task (name, project_id, parent_id) values ($1, $2, $3)", name, p.Id, parentId)
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	id, err := res.LastInsertId()
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
-	return &Task{
+	t := &Task{
 		Id:      id,
-		Name:    name,
+		Name:    path,
 		Project: p,
 	}
+	if parent != nil {
+		t.ParentId = parent.Id
+	}
+	return t
+}
+
+// Split a task path into the names of each task, ignoring empty names
+func splitTaskPath(path string) (segments []string) {
+	for _, s := range strings.Split(path, "/") {
+		if s = strings.TrimSpace(s); s != "" {
+			segments = append(segments, s)
+		}
+	}
+	if len(segments) == 0 {
+		segments = []string{path}
+	}
+	return
+}
+
+func (p *Project) GetTotal() (d time.Duration) {
+	rows, err := db.Db.Query(`
+		select
+			coalesce(sum(strftime("%s", coalesce(end_time, datetime('now'))) - strftime("%s", start_time)), 0) as total
+		from frame f
+		left join task t on t.id = f.task_id
+		where t.project_id = $1
+	`, p.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		rows.Scan(&d)
+		d *= time.Second
+	}
+	return
+}
+
+func (p *Project) SetBudget(d time.Duration) {
+	var budget interface{}
+	if d != 0 {
+		budget = int64(d / time.Second)
+	}
+	_, err := db.Db.Exec("update project set budget = $1 where id = $2", budget, p.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	p.Budget = d
+}
+
+// GetProjectTotals returns the time spent on each project by the id of the
+// project, for commands which show the totals of many projects
+func GetProjectTotals() map[int64]time.Duration {
+	rows, err := db.Db.Query(`
+		select
+			t.project_id,
+			sum(strftime("%s", coalesce(f.end_time, datetime('now'))) - strftime("%s", f.start_time)) as total
+		from frame f
+		join task t on t.id = f.task_id
+		group by t.project_id
+	`)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+
+	totals := make(map[int64]time.Duration)
+	for rows.Next() {
+		var id int64
+		var d time.Duration
+		rows.Scan(&id, &d)
+		totals[id] = d * time.Second
+	}
+	return totals
 }
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/db"
+)
+
+// ReportEntry is the time spent on a task for a monthly report
+type ReportEntry struct {
+	Task      *Task
+	StartTime time.Time
+	EndTime   time.Time
+	Duration  time.Duration
+	Monthly   bool
+}
+
+// GetReport returns the time spent on each task finished between from and
+// to, ordered by project name and start time. Monthly tasks, or all tasks if
+// monthly is set, only include the part of each frame which falls within the
+// range, so frames spanning the start or end of the range are split.
+func GetReport(from, to time.Time, monthly bool) (entries []*ReportEntry) {
+	rows, err := db.Db.Query(`
+		select
+			coalesce(iif(
+				t.monthly or ?3,
+				(select min(start_time) from frame f2 where task_id = t.id and `+db.Epoch("f2.end_time")+` > ?1),
+				min(f.start_time)
+			), '') start_time,
+			coalesce(iif(
+				t.monthly or ?3,
+				(select max(end_time) from frame f2 where task_id = t.id and `+db.Epoch("f2.start_time")+` < ?2),
+				max(f.end_time)
+			), '') end_time,
+			iif(
+				t.monthly or ?3,
+				sum(`+db.ClippedSeconds("f", "?1", "?2")+`),
+				sum(`+db.Epoch("f.end_time")+` - `+db.Epoch("f.start_time")+`)
+			) total,
+			(t.monthly or ?3) monthly,
+			t.id
+		from task t
+		left join frame f on f.task_id = t.id
+		left join project p on p.id = t.project_id
+		group by t.id
+		having
+			(`+db.Epoch("max(f.end_time)")+` >= ?1 and `+db.Epoch("max(f.end_time)")+` < ?2) or ((monthly or ?3) = true and total > 0)
+		order by p.name, start_time;
+	`, from.Unix(), to.Unix(), monthly)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+
+	var taskIds []int64
+	for rows.Next() {
+		e := &ReportEntry{}
+		var taskId int64
+		var startTime, endTime string
+		rows.Scan(&startTime, &endTime, &e.Duration, &e.Monthly, &taskId)
+		e.StartTime = db.ParseTime(startTime)
+		e.EndTime = db.ParseTime(endTime)
+		e.Duration *= time.Second
+		entries = append(entries, e)
+		taskIds = append(taskIds, taskId)
+	}
+	rows.Close()
+
+	for i, e := range entries {
+		t := GetTaskById(taskIds[i])
+		e.Task = &t
+	}
+	return
+}
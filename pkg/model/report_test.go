@@ -0,0 +1,205 @@
+package model
+
+import (
+	"os"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/jasonwoodland/track/pkg/db"
+)
+
+func TestMain(m *testing.M) {
+	// Berlin changes from summer time on 2026-10-25, so that day is 25 hours
+	if err := db.SetTimezone("Europe/Berlin"); err != nil {
+		panic(err)
+	}
+	db.Open("file:model?mode=memory&cache=shared")
+	os.Exit(m.Run())
+}
+
+func localTime(v string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", v, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+type testFrame struct {
+	task  string
+	start string
+	end   string
+}
+
+// Replace the projects, tasks and frames with a project with the frames
+func addTestFrames(t *testing.T, frames []testFrame) {
+	t.Helper()
+	if _, err := db.Db.Exec("delete from project"); err != nil {
+		t.Fatal(err)
+	}
+	p := AddProject("acme")
+	for _, f := range frames {
+		task := p.GetTask(f.task)
+		if task == nil {
+			task = p.AddTask(f.task)
+		}
+		AddFrame(task, localTime(f.start), localTime(f.end))
+	}
+}
+
+// Frames used by the tests, which cross midnight, the change from summer
+// time and the end of the month
+var (
+	midnightFrame = testFrame{"midnight", "2026-10-14 23:00", "2026-10-15 01:00"}
+	dstFrame      = testFrame{"dst", "2026-10-24 22:00", "2026-10-25 04:00"}
+	monthFrame    = testFrame{"month", "2026-10-31 22:00", "2026-11-01 02:00"}
+)
+
+func TestGetReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		frames  []testFrame
+		month   string
+		monthly bool
+		want    map[string]time.Duration
+	}{
+		{
+			"across midnight",
+			[]testFrame{midnightFrame},
+			"2026-10-01", false,
+			map[string]time.Duration{"midnight": 2 * time.Hour},
+		},
+		{
+			"across dst change",
+			[]testFrame{dstFrame},
+			"2026-10-01", false,
+			map[string]time.Duration{"dst": 7 * time.Hour},
+		},
+		{
+			"across dst change monthly",
+			[]testFrame{dstFrame},
+			"2026-10-01", true,
+			map[string]time.Duration{"dst": 7 * time.Hour},
+		},
+		{
+			"ending after the month",
+			[]testFrame{monthFrame},
+			"2026-10-01", false,
+			map[string]time.Duration{},
+		},
+		{
+			"ending in the month",
+			[]testFrame{monthFrame},
+			"2026-11-01", false,
+			map[string]time.Duration{"month": 4 * time.Hour},
+		},
+		{
+			"end of month monthly",
+			[]testFrame{monthFrame},
+			"2026-10-01", true,
+			map[string]time.Duration{"month": 2 * time.Hour},
+		},
+		{
+			"start of month monthly",
+			[]testFrame{monthFrame},
+			"2026-11-01", true,
+			map[string]time.Duration{"month": 2 * time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addTestFrames(t, tt.frames)
+			from := localTime(tt.month + " 00:00")
+			got := make(map[string]time.Duration)
+			for _, e := range GetReport(from, from.AddDate(0, 1, 0), tt.monthly) {
+				got[e.Task.Name] = e.Duration
+			}
+			checkDurations(t, got, tt.want)
+		})
+	}
+}
+
+func TestGetLog(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames []testFrame
+		from   string
+		to     string
+		want   map[string]time.Duration
+	}{
+		{
+			"before midnight",
+			[]testFrame{midnightFrame},
+			"2026-10-14 00:00", "2026-10-15 00:00",
+			map[string]time.Duration{"midnight": time.Hour},
+		},
+		{
+			"after midnight",
+			[]testFrame{midnightFrame},
+			"2026-10-15 00:00", "2026-10-16 00:00",
+			map[string]time.Duration{"midnight": time.Hour},
+		},
+		{
+			"before dst change",
+			[]testFrame{dstFrame},
+			"2026-10-24 00:00", "2026-10-25 00:00",
+			map[string]time.Duration{"dst": 2 * time.Hour},
+		},
+		{
+			"after dst change",
+			[]testFrame{dstFrame},
+			"2026-10-25 00:00", "2026-10-26 00:00",
+			map[string]time.Duration{"dst": 5 * time.Hour},
+		},
+		{
+			"end of month",
+			[]testFrame{monthFrame},
+			"2026-10-01 00:00", "2026-11-01 00:00",
+			map[string]time.Duration{"month": 2 * time.Hour},
+		},
+		{
+			"start of month",
+			[]testFrame{monthFrame},
+			"2026-11-01 00:00", "2026-12-01 00:00",
+			map[string]time.Duration{"month": 2 * time.Hour},
+		},
+		{
+			"all frames",
+			[]testFrame{midnightFrame, dstFrame, monthFrame},
+			"2026-10-01 00:00", "2026-12-01 00:00",
+			map[string]time.Duration{"midnight": 2 * time.Hour, "dst": 7 * time.Hour, "month": 4 * time.Hour},
+		},
+		{
+			"outside range",
+			[]testFrame{midnightFrame},
+			"2026-10-16 00:00", "2026-10-17 00:00",
+			map[string]time.Duration{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addTestFrames(t, tt.frames)
+			got := make(map[string]time.Duration)
+			for _, e := range GetLog(localTime(tt.from), localTime(tt.to), "", "") {
+				got[e.Task.Name] = e.Duration
+			}
+			checkDurations(t, got, tt.want)
+		})
+	}
+}
+
+func checkDurations(t *testing.T, got, want map[string]time.Duration) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("got %v, want %v", got, want)
+		return
+	}
+	for task, d := range want {
+		if got[task] != d {
+			t.Errorf("%s = %v, want %v", task, got[task], d)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package model
 
 import (
-	"log"
 	"time"
 
 	"github.com/jasonwoodland/track/pkg/db"
@@ -14,20 +13,41 @@ type State struct {
 	TimeElapsed time.Duration
 }
 
+// GetState returns the running task, loading the task and its project in a
+// single query so it's cheap enough to call every second
 func GetState() (s *State) {
 	s = &State{}
-	rows, err := db.Db.Query("select task_id, start_time from frame where end_time is null")
+	rows, err := db.Db.Query(`
+		select
+			t.id,
+			tp.path,
+			coalesce(t.estimate, 0),
+			p.id,
+			p.name,
+			coalesce(p.budget, 0),
+			coalesce(p.client_id, 0),
+			coalesce(p.color, ''),
+			coalesce(t.color, ''),
+			f.start_time
+		from frame f
+		join task t on t.id = f.task_id
+		join task_path tp on tp.id = t.id
+		join project p on p.id = t.project_id
+		where f.end_time is null
+	`)
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	if rows.Next() {
 		s.Running = true
-		var taskId int64
+		p := &Project{}
 		var startTime string
-		rows.Scan(&taskId, &startTime)
-		s.Task = GetTaskById(taskId)
-		s.StartTime, _ = time.Parse(time.RFC3339, startTime)
+		rows.Scan(&s.Task.Id, &s.Task.Name, &s.Task.Estimate, &p.Id, &p.Name, &p.Budget, &p.ClientId, &p.Color, &s.Task.Color, &startTime)
+		s.Task.Estimate *= time.Second
+		p.Budget *= time.Second
+		s.Task.Project = p
+		s.StartTime = db.ParseTime(startTime)
 		s.TimeElapsed = time.Now().Sub(s.StartTime)
 	}
 	return
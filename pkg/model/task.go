@@ -1,22 +1,44 @@
 package model
 
 import (
-	"log"
+	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jasonwoodland/track/pkg/db"
 )
 
+// Task is a task or subtask on a project. The name of a subtask is its path,
+// which is prefixed with the names of its parents (eg. frontend/login/oauth).
 type Task struct {
-	Id      int64
-	Name    string
-	Project *Project
+	Id       int64
+	Name     string
+	Project  *Project
+	Estimate time.Duration
+	ParentId int64
+	Archived bool
+
+	// Metadata shown alongside the task, like the project's
+	Description string
+	Color       string
+	Ref         string
 }
 
 func GetTaskById(id int64) (t Task) {
-	rows, err := db.Db.Query("select id, name, project_id from task where id = $1", id)
+	return getTaskById(db.Db, id)
+}
+
+func getTaskById(q db.Querier, id int64) (t Task) {
+	rows, err := q.Query(`
+		select t.id, tp.path, t.project_id, coalesce(t.parent_id, 0), coalesce(t.estimate, 0), coalesce(t.archived, false),
+			coalesce(t.description, ''), coalesce(t.color, ''), coalesce(t.ref, '')
+		from task t
+		join task_path tp on tp.id = t.id
+		where t.id = $1
+	`, id)
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	if rows.Next() {
@@ -24,7 +46,8 @@ func GetTaskById(id int64) (t Task) {
 			Id: id,
 		}
 		var projectId int64
-		rows.Scan(&t.Id, &t.Name, &projectId)
+		rows.Scan(&t.Id, &t.Name, &projectId, &t.ParentId, &t.Estimate, &t.Archived, &t.Description, &t.Color, &t.Ref)
+		t.Estimate *= time.Second
 		t.Project = GetProjectById(projectId)
 	}
 	return
@@ -33,7 +56,7 @@ func GetTaskById(id int64) (t Task) {
 func (t *Task) GetNumFrames() (n int) {
 	rows, err := db.Db.Query("select count(*) from frame where task_id = $1", t.Id)
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	if rows.Next() {
@@ -44,9 +67,9 @@ func (t *Task) GetNumFrames() (n int) {
 }
 
 func (t *Task) GetFrames() (frames []*Frame) {
-	rows, err := db.Db.Query("select id, start_time, end_time from frame where task_id = $1", t.Id)
+	rows, err := db.Db.Query("select id, start_time, coalesce(end_time, ''), coalesce(note, '') from frame where task_id = $1", t.Id)
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	for rows.Next() {
@@ -54,23 +77,24 @@ func (t *Task) GetFrames() (frames []*Frame) {
 			Task: t,
 		}
 		var startTime, endTime string
-		rows.Scan(&f.Id, &startTime, &endTime)
-		f.StartTime, _ = time.Parse(time.RFC3339, startTime)
-		f.EndTime, _ = time.Parse(time.RFC3339, endTime)
+		rows.Scan(&f.Id, &startTime, &endTime, &f.Note)
+		f.StartTime = db.ParseTime(startTime)
+		f.EndTime = db.ParseTime(endTime)
 		frames = append(frames, f)
 	}
 	return
 }
 
+// GetTotal returns the time spent on the task and its subtasks
 func (t *Task) GetTotal() (d time.Duration) {
 	rows, err := db.Db.Query(`
 		select
-			sum(strftime("%s", coalesce(end_time, datetime('now'))) - strftime("%s", start_time)) as total
+			coalesce(sum(strftime("%s", coalesce(end_time, datetime('now'))) - strftime("%s", start_time)), 0) as total
 		from frame
-		where task_id = $1
+		where task_id in (select task_id from task_tree where ancestor_id = $1)
 	`, t.Id)
 	if err != nil {
-		log.Fatal(err)
+		db.Fatal(err)
 	}
 	defer rows.Close()
 	if rows.Next() {
@@ -79,3 +103,217 @@ func (t *Task) GetTotal() (d time.Duration) {
 	}
 	return
 }
+
+// GetTaskTotals returns the time spent on each task and its subtasks by the
+// id of the task, for commands which show the totals of many tasks
+func GetTaskTotals() map[int64]time.Duration {
+	rows, err := db.Db.Query(`
+		select
+			tt.ancestor_id,
+			sum(strftime("%s", coalesce(f.end_time, datetime('now'))) - strftime("%s", f.start_time)) as total
+		from frame f
+		join task_tree tt on tt.task_id = f.task_id
+		group by tt.ancestor_id
+	`)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+
+	totals := make(map[int64]time.Duration)
+	for rows.Next() {
+		var id int64
+		var d time.Duration
+		rows.Scan(&id, &d)
+		totals[id] = d * time.Second
+	}
+	return totals
+}
+
+// GetOwnTaskTotals returns the time spent on each task by the id of the task,
+// without the time spent on its subtasks
+func GetOwnTaskTotals() map[int64]time.Duration {
+	rows, err := db.Db.Query(`
+		select
+			task_id,
+			sum(strftime("%s", coalesce(end_time, datetime('now'))) - strftime("%s", start_time)) as total
+		from frame
+		group by task_id
+	`)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+
+	totals := make(map[int64]time.Duration)
+	for rows.Next() {
+		var id int64
+		var d time.Duration
+		rows.Scan(&id, &d)
+		totals[id] = d * time.Second
+	}
+	return totals
+}
+
+// GetSubtasks returns the subtasks of the task, and their subtasks
+func (t *Task) GetSubtasks() (tasks []*Task) {
+	return t.getSubtasks(db.Db)
+}
+
+func (t *Task) getSubtasks(q db.Querier) (tasks []*Task) {
+	rows, err := q.Query(`
+		select task_id from task_tree where ancestor_id = $1 and task_id != $1 order by task_id
+	`, t.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		rows.Scan(&id)
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		subtask := getTaskById(q, id)
+		tasks = append(tasks, &subtask)
+	}
+	return
+}
+
+// IsSubtaskOf is true if the task is a subtask of the other task, or one of
+// its subtasks
+func (t *Task) IsSubtaskOf(other *Task) bool {
+	for _, s := range other.GetSubtasks() {
+		if s.Id == t.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// RenamedPath returns the task's path after renaming it to name. A bare name
+// keeps the task's parent, a path (eg. frontend/login) moves it to another
+// parent and a name starting with / moves it to the top level.
+func (t *Task) RenamedPath(name string) string {
+	segments := splitTaskPath(name)
+	if len(segments) == 1 && !strings.HasPrefix(strings.TrimSpace(name), "/") {
+		if i := strings.LastIndex(t.Name, "/"); i != -1 {
+			return t.Name[:i+1] + segments[0]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Rename renames the task, moving it and its subtasks to the parent given by
+// RenamedPath. Parents which don't exist yet are added.
+func (t *Task) Rename(name string) error {
+	path := t.RenamedPath(name)
+	segments := splitTaskPath(path)
+	parentPath := strings.Join(segments[:len(segments)-1], "/")
+	if parentPath == t.Name || strings.HasPrefix(parentPath, t.Name+"/") {
+		return fmt.Errorf("can't move %s into its own subtask %s", t.Name, parentPath)
+	}
+
+	err := db.Transaction(func(tx *sql.Tx) error {
+		var parentId interface{}
+		if parentPath != "" {
+			parent := t.Project.getTask(tx, parentPath)
+			if parent == nil {
+				parent = t.Project.addTaskPath(tx, parentPath)
+			}
+			parentId = parent.Id
+		}
+		_, err := tx.Exec(
+			"update task set name = $1, parent_id = $2 where id = $3",
+			segments[len(segments)-1],
+			parentId,
+			t.Id,
+		)
+		if err != nil {
+			db.Fatal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*t = GetTaskById(t.Id)
+	return nil
+}
+
+// Remove deletes the task and its subtasks, and their frames
+func (t *Task) Remove() {
+	_, err := db.Db.Exec("delete from task where id = $1", t.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+// Merge moves the frames of the task to another task and deletes it. Its
+// subtasks become subtasks of the other task, or are merged into the other
+// task's subtasks with the same name.
+func (t *Task) Merge(into *Task) error {
+	if into.Id == t.Id || into.IsSubtaskOf(t) {
+		return fmt.Errorf("can't merge %s into its own subtask %s", t.Name, into.Name)
+	}
+	return db.Transaction(func(tx *sql.Tx) error {
+		t.merge(tx, into)
+		return nil
+	})
+}
+
+func (t *Task) merge(q db.Querier, into *Task) {
+	_, err := q.Exec("update frame set task_id = $1 where task_id = $2", into.Id, t.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+
+	for _, child := range t.getSubtasks(q) {
+		if child.ParentId != t.Id {
+			continue
+		}
+		name := strings.TrimPrefix(child.Name, t.Name+"/")
+		if existing := into.Project.getTask(q, into.Name+"/"+name); existing != nil {
+			child.merge(q, existing)
+			continue
+		}
+		_, err := q.Exec(
+			"update task set project_id = $1, parent_id = $2 where id = $3",
+			into.Project.Id,
+			into.Id,
+			child.Id,
+		)
+		if err != nil {
+			db.Fatal(err)
+		}
+		// Subtasks of the moved subtask move with it, but may be on another
+		// project
+		_, err = q.Exec(
+			"update task set project_id = $1 where id in (select task_id from task_tree where ancestor_id = $2)",
+			into.Project.Id,
+			child.Id,
+		)
+		if err != nil {
+			db.Fatal(err)
+		}
+	}
+
+	_, err = q.Exec("delete from task where id = $1", t.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+func (t *Task) SetEstimate(d time.Duration) {
+	var estimate interface{}
+	if d != 0 {
+		estimate = int64(d / time.Second)
+	}
+	_, err := db.Db.Exec("update task set estimate = $1 where id = $2", estimate, t.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	t.Estimate = d
+}
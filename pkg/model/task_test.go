@@ -0,0 +1,108 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	addTestFrames(t, []testFrame{
+		{"old/login", "2026-10-14 09:00", "2026-10-14 10:00"},
+		{"old/login/oauth", "2026-10-14 10:00", "2026-10-14 11:00"},
+		{"old/signup", "2026-10-14 11:00", "2026-10-14 12:00"},
+		{"new/login", "2026-10-14 13:00", "2026-10-14 14:00"},
+	})
+	p := GetProjectByName("acme")
+
+	if err := p.GetTask("old").Merge(p.GetTask("new")); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.GetTask("old") != nil {
+		t.Error("old wasn't removed")
+	}
+	want := map[string]time.Duration{
+		"new":             4 * time.Hour,
+		"new/login":       3 * time.Hour,
+		"new/login/oauth": time.Hour,
+		"new/signup":      time.Hour,
+	}
+	for path, d := range want {
+		task := p.GetTask(path)
+		if task == nil {
+			t.Errorf("%s doesn't exist", path)
+			continue
+		}
+		if total := task.GetTotal(); total != d {
+			t.Errorf("%s total = %v, want %v", path, total, d)
+		}
+	}
+
+	if err := p.GetTask("new").Merge(p.GetTask("new/login")); err == nil {
+		t.Error("merged a task into its own subtask")
+	}
+}
+
+func TestRename(t *testing.T) {
+	addTestFrames(t, []testFrame{
+		{"frontend/login", "2026-10-14 09:00", "2026-10-14 10:00"},
+		{"frontend/login/oauth", "2026-10-14 10:00", "2026-10-14 11:00"},
+	})
+	p := GetProjectByName("acme")
+
+	tests := []struct {
+		from string
+		name string
+		want string
+	}{
+		{"frontend/login", "signin", "frontend/signin"},
+		{"frontend/signin", "backend/auth/signin", "backend/auth/signin"},
+		{"backend/auth/signin", "/signin", "signin"},
+	}
+	for _, tt := range tests {
+		task := p.GetTask(tt.from)
+		if err := task.Rename(tt.name); err != nil {
+			t.Fatal(err)
+		}
+		if task.Name != tt.want {
+			t.Errorf("renamed %s to %s, want %s", tt.from, task.Name, tt.want)
+		}
+		if p.GetTask(tt.want+"/oauth") == nil {
+			t.Errorf("%s/oauth doesn't exist", tt.want)
+		}
+	}
+
+	tasks := len(p.GetTasks())
+	if err := p.GetTask("signin").Rename("signin/oauth/new/signin"); err == nil {
+		t.Error("moved a task into its own subtask")
+	}
+	if n := len(p.GetTasks()); n != tasks {
+		t.Errorf("rejected rename added %d tasks", n-tasks)
+	}
+}
+
+func TestGetTotals(t *testing.T) {
+	addTestFrames(t, []testFrame{
+		{"login", "2026-10-14 09:00", "2026-10-14 10:00"},
+		{"login/oauth", "2026-10-14 10:00", "2026-10-14 12:00"},
+		{"signup", "2026-10-14 13:00", "2026-10-14 14:00"},
+	})
+	p := GetProjectByName("acme")
+
+	taskTotals := GetTaskTotals()
+	for _, task := range p.GetTasks() {
+		if taskTotals[task.Id] != task.GetTotal() {
+			t.Errorf("%s total = %v, want %v", task.Name, taskTotals[task.Id], task.GetTotal())
+		}
+	}
+	own := map[string]time.Duration{"login": time.Hour, "login/oauth": 2 * time.Hour, "signup": time.Hour}
+	ownTotals := GetOwnTaskTotals()
+	for path, d := range own {
+		if task := p.GetTask(path); ownTotals[task.Id] != d {
+			t.Errorf("%s own total = %v, want %v", path, ownTotals[task.Id], d)
+		}
+	}
+	if d := GetProjectTotals()[p.Id]; d != p.GetTotal() {
+		t.Errorf("project total = %v, want %v", d, p.GetTotal())
+	}
+}
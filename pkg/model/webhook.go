@@ -0,0 +1,209 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/db"
+)
+
+type Webhook struct {
+	Id     int64
+	Url    string
+	Events []string
+}
+
+// OutboxEntry is an event waiting to be delivered to a webhook
+type OutboxEntry struct {
+	Id          int64
+	Webhook     *Webhook
+	Event       string
+	Payload     []byte
+	Attempts    int
+	NextAttempt time.Time
+	LastError   string
+}
+
+func GetWebhooks() (webhooks []*Webhook) {
+	rows, err := db.Db.Query("select id, url, coalesce(events, '') from webhook order by id")
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		w := &Webhook{}
+		var events string
+		rows.Scan(&w.Id, &w.Url, &events)
+		w.Events = splitEvents(events)
+		webhooks = append(webhooks, w)
+	}
+	return
+}
+
+func GetWebhookById(id int64) (w *Webhook) {
+	rows, err := db.Db.Query("select url, coalesce(events, '') from webhook where id = $1", id)
+	if err != nil {
+		db.Fatal(err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		w = &Webhook{Id: id}
+		var events string
+		rows.Scan(&w.Url, &events)
+		w.Events = splitEvents(events)
+	}
+	return
+}
+
+// AddWebhook adds a webhook for the events, or for every event if there are
+// none
+func AddWebhook(url string, events []string) *Webhook {
+	res, err := db.Db.Exec(
+		"insert into webhook (url, events) values ($1, $2)",
+		url,
+		strings.Join(events, ","),
+	)
+	if err != nil {
+		db.Fatal(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		db.Fatal(err)
+	}
+	return &Webhook{
+		Id:     id,
+		Url:    url,
+		Events: events,
+	}
+}
+
+// Remove deletes the webhook and any events which haven't been delivered
+func (w *Webhook) Remove() {
+	_, err := db.Db.Exec("delete from webhook where id = $1", w.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+// Wants is true if the webhook is subscribed to the event
+func (w *Webhook) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPending returns the number of events waiting to be delivered to the
+// webhook, and the error from the last failed attempt
+func (w *Webhook) GetPending() (n int, lastError string) {
+	err := db.Db.QueryRow(`
+		select
+			count(*),
+			coalesce((
+				select last_error from webhook_outbox
+				where webhook_id = $1 and last_error is not null
+				order by id desc
+				limit 1
+			), '')
+		from webhook_outbox
+		where webhook_id = $1
+	`, w.Id).Scan(&n, &lastError)
+	if err != nil {
+		db.Fatal(err)
+	}
+	return
+}
+
+// Enqueue adds an event to the outbox, to be delivered as soon as possible
+func (w *Webhook) Enqueue(event string, payload []byte) {
+	_, err := db.Db.Exec(
+		"insert into webhook_outbox (webhook_id, event, payload, next_attempt) values ($1, $2, $3, $4)",
+		w.Id,
+		event,
+		string(payload),
+		db.FormatTime(time.Now()),
+	)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+// ClaimDueOutbox returns the events which are due to be delivered, pushing
+// their next attempt back by lease so another process delivering the outbox
+// at the same time doesn't also send them
+func ClaimDueOutbox(lease time.Duration) (entries []*OutboxEntry) {
+	now := time.Now()
+	rows, err := db.Db.Query(`
+		select o.id, o.event, o.payload, o.attempts, o.next_attempt, coalesce(o.last_error, ''), w.id, w.url
+		from webhook_outbox o
+		join webhook w on w.id = o.webhook_id
+		where o.next_attempt <= $1
+		order by o.id
+	`, db.FormatTime(now))
+	if err != nil {
+		db.Fatal(err)
+	}
+	var due []*OutboxEntry
+	for rows.Next() {
+		e := &OutboxEntry{Webhook: &Webhook{}}
+		var payload, nextAttempt string
+		rows.Scan(&e.Id, &e.Event, &payload, &e.Attempts, &nextAttempt, &e.LastError, &e.Webhook.Id, &e.Webhook.Url)
+		e.Payload = []byte(payload)
+		e.NextAttempt = db.ParseTime(nextAttempt)
+		due = append(due, e)
+	}
+	rows.Close()
+
+	for _, e := range due {
+		res, err := db.Db.Exec(
+			"update webhook_outbox set next_attempt = $1 where id = $2 and next_attempt = $3",
+			db.FormatTime(now.Add(lease)),
+			e.Id,
+			db.FormatTime(e.NextAttempt),
+		)
+		if err != nil {
+			db.Fatal(err)
+		}
+		if n, _ := res.RowsAffected(); n == 1 {
+			entries = append(entries, e)
+		}
+	}
+	return
+}
+
+// Delivered removes the event from the outbox
+func (e *OutboxEntry) Delivered() {
+	_, err := db.Db.Exec("delete from webhook_outbox where id = $1", e.Id)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+// Failed records a failed attempt to deliver the event, and when to try again
+func (e *OutboxEntry) Failed(deliveryErr error, nextAttempt time.Time) {
+	e.Attempts++
+	e.NextAttempt = nextAttempt
+	e.LastError = deliveryErr.Error()
+	_, err := db.Db.Exec(
+		"update webhook_outbox set attempts = $1, next_attempt = $2, last_error = $3 where id = $4",
+		e.Attempts,
+		db.FormatTime(e.NextAttempt),
+		e.LastError,
+		e.Id,
+	)
+	if err != nil {
+		db.Fatal(err)
+	}
+}
+
+func splitEvents(events string) []string {
+	if events == "" {
+		return nil
+	}
+	return strings.Split(events, ",")
+}
@@ -12,6 +12,6 @@ func (t *Time) Scan(v interface{}) error {
 	if err != nil {
 		return err
 	}
-	*t = Time(vt)
+	*t = Time(vt.Local())
 	return nil
 }
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/hooks"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/webhook"
+)
+
+type frameRequest struct {
+	TaskId    *int64     `json:"task_id"`
+	StartTime *time.Time `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+	Note      *string    `json:"note"`
+}
+
+func (s *Server) handleFrames(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		from, ok := timeFromQuery(w, r, "from", time.Time{})
+		if !ok {
+			return
+		}
+		to, ok := timeFromQuery(w, r, "to", time.Now().AddDate(100, 0, 0))
+		if !ok {
+			return
+		}
+
+		var taskId int64
+		if v := r.URL.Query().Get("task_id"); v != "" {
+			taskId, _ = strconv.ParseInt(v, 10, 64)
+		}
+
+		res := []*frameJSON{}
+		for _, f := range model.GetFrames(from, to) {
+			if taskId == 0 || f.Task.Id == taskId {
+				res = append(res, newFrameJSON(f))
+			}
+		}
+		writeJSON(w, http.StatusOK, res)
+
+	case http.MethodPost:
+		req := frameRequest{}
+		if !readJSON(w, r, &req) {
+			return
+		}
+		if req.TaskId == nil || req.StartTime == nil || req.EndTime == nil {
+			writeError(w, http.StatusBadRequest, "task_id, start_time and end_time are required")
+			return
+		}
+		if !req.EndTime.After(*req.StartTime) {
+			writeError(w, http.StatusBadRequest, "end_time must be after start_time")
+			return
+		}
+		task := model.GetTaskById(*req.TaskId)
+		if task.Project == nil {
+			writeError(w, http.StatusNotFound, "task doesn't exist")
+			return
+		}
+
+		frame := model.AddFrame(&task, req.StartTime.Local(), req.EndTime.Local())
+		if req.Note != nil {
+			frame.Note = *req.Note
+			frame.Update()
+		}
+		runPostHook(hooks.PostEdit, hooks.ActionAdd, frame)
+		webhook.NotifyGoals(frame)
+		writeJSON(w, http.StatusCreated, newFrameJSON(frame))
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleFrame(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, "/frames/")
+	if !ok {
+		return
+	}
+
+	frame := model.GetFrameById(id)
+	if frame == nil {
+		writeError(w, http.StatusNotFound, "frame doesn't exist")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, newFrameJSON(frame))
+
+	case http.MethodPatch:
+		req := frameRequest{}
+		if !readJSON(w, r, &req) {
+			return
+		}
+		// Stopping the running frame goes through /stop so it runs the
+		// post-stop hook and notifies webhooks
+		if req.EndTime != nil && frame.IsRunning() {
+			writeError(w, http.StatusConflict, "frame is running, use POST /stop to stop it")
+			return
+		}
+		action := hooks.ActionEdit
+		if req.TaskId != nil && *req.TaskId != frame.Task.Id {
+			action = hooks.ActionMove
+		}
+		if req.TaskId != nil {
+			task := model.GetTaskById(*req.TaskId)
+			if task.Project == nil {
+				writeError(w, http.StatusNotFound, "task doesn't exist")
+				return
+			}
+			frame.Task = &task
+		}
+		if req.StartTime != nil {
+			frame.StartTime = req.StartTime.Local()
+		}
+		if req.EndTime != nil {
+			frame.EndTime = req.EndTime.Local()
+		}
+		if req.Note != nil {
+			frame.Note = *req.Note
+		}
+		if !frame.IsRunning() && !frame.EndTime.After(frame.StartTime) {
+			writeError(w, http.StatusBadRequest, "end_time must be after start_time")
+			return
+		}
+		frame.Update()
+		runPostHook(hooks.PostEdit, action, frame)
+		writeJSON(w, http.StatusOK, newFrameJSON(frame))
+
+	case http.MethodDelete:
+		frame.Remove()
+		runPostHook(hooks.PostEdit, hooks.ActionRemove, frame)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPatch, http.MethodDelete)
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"log"
+	"sync"
+
+	"github.com/jasonwoodland/track/pkg/hooks"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/webhook"
+)
+
+// Post-* hooks are run in order by a single goroutine, so slow hooks don't
+// hold the lock and block other requests
+var (
+	postHooks     = make(chan func(), 100)
+	postHooksOnce sync.Once
+)
+
+// Run a post-* hook in the background, logging a failure since the change has
+// already been made
+func runPostHook(hook, action string, f *model.Frame) {
+	postHooksOnce.Do(func() {
+		go func() {
+			for run := range postHooks {
+				run()
+			}
+		}()
+	})
+
+	// The hook is given the frame as it was when it changed
+	frame := *f
+	task := *f.Task
+	project := *task.Project
+	task.Project = &project
+	frame.Task = &task
+
+	postHooks <- func() {
+		if err := hooks.Run(hook, action, &frame); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// Stop the running frame, running the post-stop hook and notifying webhooks
+func stopFrame(f *model.Frame) {
+	model.StopFrame(f.EndTime)
+	runPostHook(hooks.PostStop, hooks.ActionStop, f)
+	webhook.Notify(webhook.EventStop, f)
+	webhook.NotifyGoals(f)
+}
+
+// Run the post-edit hook for each of the frames
+func runPostEditHooks(action string, frames []*model.Frame) {
+	for _, f := range frames {
+		runPostHook(hooks.PostEdit, action, f)
+	}
+}
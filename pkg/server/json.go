@@ -0,0 +1,141 @@
+package server
+
+import (
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/model"
+)
+
+type projectJSON struct {
+	Id            int64  `json:"id"`
+	Name          string `json:"name"`
+	ClientId      int64  `json:"client_id,omitempty"`
+	BudgetSeconds int64  `json:"budget_seconds,omitempty"`
+	TotalSeconds  int64  `json:"total_seconds"`
+	Archived      bool   `json:"archived,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Color         string `json:"color,omitempty"`
+	Ref           string `json:"ref,omitempty"`
+}
+
+type taskJSON struct {
+	Id              int64  `json:"id"`
+	Name            string `json:"name"`
+	ProjectId       int64  `json:"project_id"`
+	Project         string `json:"project"`
+	ParentId        int64  `json:"parent_id,omitempty"`
+	EstimateSeconds int64  `json:"estimate_seconds,omitempty"`
+	TotalSeconds    int64  `json:"total_seconds"`
+	Archived        bool   `json:"archived,omitempty"`
+	Description     string `json:"description,omitempty"`
+	Color           string `json:"color,omitempty"`
+	Ref             string `json:"ref,omitempty"`
+}
+
+type frameJSON struct {
+	Id        int64      `json:"id"`
+	TaskId    int64      `json:"task_id"`
+	Task      string     `json:"task"`
+	ProjectId int64      `json:"project_id"`
+	Project   string     `json:"project"`
+	StartTime time.Time  `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+	Seconds   int64      `json:"seconds"`
+	Note      string     `json:"note,omitempty"`
+}
+
+type stateJSON struct {
+	Running        bool       `json:"running"`
+	Task           *taskJSON  `json:"task,omitempty"`
+	StartTime      *time.Time `json:"start_time,omitempty"`
+	ElapsedSeconds int64      `json:"elapsed_seconds,omitempty"`
+}
+
+type logEntryJSON struct {
+	TaskId         int64     `json:"task_id"`
+	Task           string    `json:"task"`
+	ProjectId      int64     `json:"project_id"`
+	Project        string    `json:"project"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	Seconds        int64     `json:"seconds"`
+	ProjectSeconds int64     `json:"project_seconds"`
+	Ref            string    `json:"ref,omitempty"`
+}
+
+type reportEntryJSON struct {
+	TaskId    int64     `json:"task_id"`
+	Task      string    `json:"task"`
+	ProjectId int64     `json:"project_id"`
+	Project   string    `json:"project"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Seconds   int64     `json:"seconds"`
+	Monthly   bool      `json:"monthly"`
+	Ref       string    `json:"ref,omitempty"`
+}
+
+func seconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
+
+func newProjectJSON(p *model.Project) *projectJSON {
+	return &projectJSON{
+		Id:            p.Id,
+		Name:          p.Name,
+		ClientId:      p.ClientId,
+		BudgetSeconds: seconds(p.Budget),
+		TotalSeconds:  seconds(p.GetTotal()),
+		Archived:      p.Archived,
+		Description:   p.Description,
+		Color:         p.Color,
+		Ref:           p.Ref,
+	}
+}
+
+func newTaskJSON(t *model.Task) *taskJSON {
+	return &taskJSON{
+		Id:              t.Id,
+		Name:            t.Name,
+		ProjectId:       t.Project.Id,
+		Project:         t.Project.Name,
+		ParentId:        t.ParentId,
+		EstimateSeconds: seconds(t.Estimate),
+		TotalSeconds:    seconds(t.GetTotal()),
+		Archived:        t.Archived,
+		Description:     t.Description,
+		Color:           t.Color,
+		Ref:             t.Ref,
+	}
+}
+
+func newFrameJSON(f *model.Frame) *frameJSON {
+	j := &frameJSON{
+		Id:        f.Id,
+		TaskId:    f.Task.Id,
+		Task:      f.Task.Name,
+		ProjectId: f.Task.Project.Id,
+		Project:   f.Task.Project.Name,
+		StartTime: f.StartTime,
+		Note:      f.Note,
+	}
+	if f.IsRunning() {
+		j.Seconds = seconds(time.Since(f.StartTime))
+	} else {
+		j.EndTime = &f.EndTime
+		j.Seconds = seconds(f.EndTime.Sub(f.StartTime))
+	}
+	return j
+}
+
+func newStateJSON(s *model.State) *stateJSON {
+	if !s.Running {
+		return &stateJSON{}
+	}
+	return &stateJSON{
+		Running:        true,
+		Task:           newTaskJSON(&s.Task),
+		StartTime:      &s.StartTime,
+		ElapsedSeconds: seconds(s.TimeElapsed),
+	}
+}
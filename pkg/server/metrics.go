@@ -0,0 +1,110 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/util"
+)
+
+// Samples of a metric keyed by task
+type taskSamples map[*model.Task]float64
+
+// Serve metrics in the Prometheus text exposition format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	weekStart := util.StartOfWeek(now, db.GetSettings().FirstDayOfWeek)
+
+	state := model.GetState()
+	running := 0.0
+	elapsed := taskSamples{}
+	if state.Running {
+		running = 1
+		elapsed[&state.Task] = state.TimeElapsed.Seconds()
+	}
+
+	var tasks []*model.Task
+	for _, p := range model.GetProjects() {
+		tasks = append(tasks, p.GetTasks()...)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Project.Name != tasks[j].Project.Name {
+			return tasks[i].Project.Name < tasks[j].Project.Name
+		}
+		return tasks[i].Name < tasks[j].Name
+	})
+
+	// Each task's own time, so summing by project doesn't count subtasks
+	// twice
+	totals := model.GetOwnTaskTotals()
+	total := taskSamples{}
+	for _, t := range tasks {
+		total[t] = totals[t.Id].Seconds()
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintln(w, "# HELP track_running Whether a task is being tracked.")
+	fmt.Fprintln(w, "# TYPE track_running gauge")
+	fmt.Fprintf(w, "track_running %g\n", running)
+
+	writeTaskMetric(w, "track_running_elapsed_seconds", "gauge", "Seconds since the running task was started.", []*model.Task{&state.Task}, elapsed)
+	writeTaskMetric(w, "track_tracked_seconds_total", "counter", "Seconds tracked on each task.", tasks, total)
+	writeTaskMetric(w, "track_today_seconds", "gauge", "Seconds tracked on each task today.", tasks, secondsBetween(tasks, today, today.AddDate(0, 0, 1)))
+	writeTaskMetric(w, "track_week_seconds", "gauge", "Seconds tracked on each task this week.", tasks, secondsBetween(tasks, weekStart, weekStart.AddDate(0, 0, 7)))
+}
+
+// Seconds tracked on each task between from and to, including the running
+// frame
+func secondsBetween(tasks []*model.Task, from, to time.Time) taskSamples {
+	samples := taskSamples{}
+	byId := make(map[int64]*model.Task)
+	for _, t := range tasks {
+		byId[t.Id] = t
+		samples[t] = 0
+	}
+
+	for _, f := range model.GetFrames(from, to) {
+		if t := byId[f.Task.Id]; t != nil {
+			samples[t] += f.DurationBetween(from, to).Seconds()
+		}
+	}
+	return samples
+}
+
+// Write a metric labelled by project and task name, with a sample for each of
+// the tasks which has one
+func writeTaskMetric(w io.Writer, name, typ, help string, tasks []*model.Task, samples taskSamples) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+	for _, t := range tasks {
+		v, ok := samples[t]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(
+			w,
+			"%s{project=\"%s\",task=\"%s\"} %g\n",
+			name,
+			escapeLabel(t.Project.Name),
+			escapeLabel(t.Name),
+			v,
+		)
+	}
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/hooks"
+	"github.com/jasonwoodland/track/pkg/model"
+)
+
+type projectRequest struct {
+	Name          *string `json:"name"`
+	BudgetSeconds *int64  `json:"budget_seconds"`
+	metadataRequest
+}
+
+func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		// Archived projects are only included with ?all=true
+		projects := model.GetProjects()
+		if r.URL.Query().Get("all") != "true" {
+			projects = model.ActiveProjects(projects)
+		}
+		sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+
+		res := []*projectJSON{}
+		for _, p := range projects {
+			res = append(res, newProjectJSON(p))
+		}
+		writeJSON(w, http.StatusOK, res)
+
+	case http.MethodPost:
+		req := projectRequest{}
+		if !readJSON(w, r, &req) || !req.checkColor(w) {
+			return
+		}
+		if req.Name == nil || *req.Name == "" {
+			writeError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		if model.GetProjectByName(*req.Name) != nil {
+			writeError(w, http.StatusConflict, "project "+*req.Name+" already exists")
+			return
+		}
+
+		project := model.AddProject(*req.Name)
+		if req.BudgetSeconds != nil {
+			project.SetBudget(time.Duration(*req.BudgetSeconds) * time.Second)
+		}
+		req.set(project)
+		writeJSON(w, http.StatusCreated, newProjectJSON(project))
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, "/projects/")
+	if !ok {
+		return
+	}
+
+	project := model.GetProjectById(id)
+	if project == nil {
+		writeError(w, http.StatusNotFound, "project doesn't exist")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, newProjectJSON(project))
+
+	case http.MethodPatch:
+		req := projectRequest{}
+		if !readJSON(w, r, &req) || !req.checkColor(w) {
+			return
+		}
+		if req.Name != nil && *req.Name != project.Name {
+			if *req.Name == "" {
+				writeError(w, http.StatusBadRequest, "name can't be empty")
+				return
+			}
+			if model.GetProjectByName(*req.Name) != nil {
+				writeError(w, http.StatusConflict, "project "+*req.Name+" already exists")
+				return
+			}
+			project.Rename(*req.Name)
+		}
+		if req.BudgetSeconds != nil {
+			project.SetBudget(time.Duration(*req.BudgetSeconds) * time.Second)
+		}
+		req.set(project)
+		writeJSON(w, http.StatusOK, newProjectJSON(project))
+
+	case http.MethodDelete:
+		var frames []*model.Frame
+		for _, t := range project.GetTasks() {
+			frames = append(frames, t.GetFrames()...)
+		}
+		project.Remove()
+		runPostEditHooks(hooks.ActionRemove, frames)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPatch, http.MethodDelete)
+	}
+}
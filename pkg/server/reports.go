@@ -0,0 +1,81 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/model"
+)
+
+// Time spent on each task between the from and to dates, like the log command
+func (s *Server) handleLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	now := time.Now()
+	from, ok := timeFromQuery(w, r, "from", time.Time{})
+	if !ok {
+		return
+	}
+	to, ok := timeFromQuery(w, r, "to", time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local))
+	if !ok {
+		return
+	}
+
+	// Include the whole of the to date
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	to = time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, time.Local)
+
+	res := []*logEntryJSON{}
+	for _, e := range model.GetLog(from, to, r.URL.Query().Get("project"), r.URL.Query().Get("task")) {
+		res = append(res, &logEntryJSON{
+			TaskId:         e.Task.Id,
+			Task:           e.Task.Name,
+			ProjectId:      e.Task.Project.Id,
+			Project:        e.Task.Project.Name,
+			StartTime:      e.StartTime,
+			EndTime:        e.EndTime,
+			Seconds:        seconds(e.Duration),
+			ProjectSeconds: seconds(e.ProjectDuration),
+			Ref:            e.Task.GetRef(),
+		})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// Time spent on each task for a month (eg. 2026-10), like the report command
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	month := time.Now()
+	if v := r.URL.Query().Get("month"); v != "" {
+		var err error
+		if month, err = time.ParseInLocation("2006-01", v, time.Local); err != nil {
+			writeError(w, http.StatusBadRequest, "bad month: "+v)
+			return
+		}
+	}
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(month.Year(), month.Month()+1, 1, 0, 0, 0, 0, time.Local)
+
+	res := []*reportEntryJSON{}
+	for _, e := range model.GetReport(from, to, r.URL.Query().Get("monthly") == "true") {
+		res = append(res, &reportEntryJSON{
+			TaskId:    e.Task.Id,
+			Task:      e.Task.Name,
+			ProjectId: e.Task.Project.Id,
+			Project:   e.Task.Project.Name,
+			StartTime: e.StartTime,
+			EndTime:   e.EndTime,
+			Seconds:   seconds(e.Duration),
+			Monthly:   e.Monthly,
+			Ref:       e.Task.GetRef(),
+		})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/view"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Server serves a JSON API for reading and controlling the timer, using the
+// same model layer as the CLI
+type Server struct {
+	token string
+	mux   *http.ServeMux
+
+	// SQLite only allows a single writer, and the model layer makes several
+	// queries for most requests, so requests which change data hold the write
+	// lock and requests which only read data hold the read lock
+	mu sync.RWMutex
+
+	// Signalled after requests which change data, which may have queued
+	// webhook events
+	changed chan struct{}
+}
+
+// New returns a server which requires the bearer token on each request, or
+// allows all requests if the token is empty
+func New(token string) *Server {
+	s := NewMetrics(token)
+	s.mux.HandleFunc("/state", s.handleState)
+	s.mux.HandleFunc("/start", s.handleStart)
+	s.mux.HandleFunc("/switch", s.handleSwitch)
+	s.mux.HandleFunc("/stop", s.handleStop)
+	s.mux.HandleFunc("/projects", s.handleProjects)
+	s.mux.HandleFunc("/projects/", s.handleProject)
+	s.mux.HandleFunc("/tasks", s.handleTasks)
+	s.mux.HandleFunc("/tasks/", s.handleTask)
+	s.mux.HandleFunc("/frames", s.handleFrames)
+	s.mux.HandleFunc("/frames/", s.handleFrame)
+	s.mux.HandleFunc("/log", s.handleLog)
+	s.mux.HandleFunc("/report", s.handleReport)
+
+	return s
+}
+
+// NewMetrics returns a server which only serves the Prometheus metrics
+// endpoint
+func NewMetrics(token string) *Server {
+	s := &Server{
+		token:   token,
+		mux:     http.NewServeMux(),
+		changed: make(chan struct{}, 1),
+	}
+
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+
+	return s
+}
+
+// dbError is a database error raised by the model layer while handling a
+// request
+type dbError struct {
+	err error
+}
+
+// DbFatal replaces db.Fatal while serving. The model layer exits on database
+// errors, which would stop the server if another process held the database
+// for too long, so the error is raised instead and fails only the request it
+// was made for.
+func DbFatal(err error) {
+	panic(dbError{err})
+}
+
+// Recover from a database error, returning it, or nil if there wasn't one.
+// Other panics are passed on.
+func recoverDbError(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	e, ok := v.(dbError)
+	if !ok {
+		panic(v)
+	}
+	log.Println(e.err)
+	return e.err
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Registered before the locks are taken so they're released first
+	defer func() {
+		if err := recoverDbError(recover()); err != nil {
+			var sqliteErr sqlite3.Error
+			if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrBusy {
+				writeError(w, http.StatusServiceUnavailable, "database is busy, try again")
+			} else {
+				writeError(w, http.StatusInternalServerError, "database error")
+			}
+		}
+	}()
+
+	if s.token != "" {
+		auth := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(auth, []byte("Bearer "+s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing or bad bearer token")
+			return
+		}
+	}
+
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	} else {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		defer func() {
+			select {
+			case s.changed <- struct{}{}:
+			default:
+			}
+		}()
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "bad request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// The metadata which can be set when adding or updating a project or task.
+// Empty strings remove the value.
+type metadataRequest struct {
+	Description *string `json:"description"`
+	Color       *string `json:"color"`
+	Ref         *string `json:"ref"`
+}
+
+// Check the color can be used, writing an error if it can't
+func (req *metadataRequest) checkColor(w http.ResponseWriter) bool {
+	if req.Color != nil && *req.Color != "" && !view.IsColor(*req.Color) {
+		writeError(w, http.StatusBadRequest, "color must be one of "+strings.Join(view.ProjectColors, ", "))
+		return false
+	}
+	return true
+}
+
+func (req *metadataRequest) set(m model.Metadata) {
+	if req.Description != nil {
+		m.SetDescription(*req.Description)
+	}
+	if req.Color != nil {
+		m.SetColor(*req.Color)
+	}
+	if req.Ref != nil {
+		m.SetRef(*req.Ref)
+	}
+}
+
+// Parse the id from a path like /projects/1, writing an error if it's missing
+func idFromPath(w http.ResponseWriter, r *http.Request, prefix string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, prefix), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return 0, false
+	}
+	return id, true
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+}
+
+// Parse a date (eg. 2026-10-19) or a time (eg. 2026-10-19T09:00:00Z) from a
+// query parameter, returning def if the parameter isn't set
+func timeFromQuery(w http.ResponseWriter, r *http.Request, name string, def time.Time) (time.Time, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, true
+	}
+	if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.Local(), true
+	}
+	writeError(w, http.StatusBadRequest, "bad time for "+name+": "+v)
+	return time.Time{}, false
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/hooks"
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/webhook"
+)
+
+type startRequest struct {
+	Project string `json:"project"`
+	Task    string `json:"task"`
+
+	// Add a new task with the exact name given, even if it is similar to an
+	// existing task
+	New bool `json:"new"`
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, http.StatusOK, newStateJSON(model.GetState()))
+}
+
+// Start a task, failing if another task is running
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+	s.start(w, r, false)
+}
+
+// Start a task, stopping the running task first
+func (s *Server) handleSwitch(w http.ResponseWriter, r *http.Request) {
+	s.start(w, r, true)
+}
+
+func (s *Server) start(w http.ResponseWriter, r *http.Request, stopRunning bool) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	req := startRequest{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Project == "" || req.Task == "" {
+		writeError(w, http.StatusBadRequest, "project and task are required")
+		return
+	}
+
+	// Resolve the task and check it can be started, writing a response if
+	// it can't
+	check := func() (project *model.Project, task *model.Task, state *model.State, ok bool) {
+		if project, ok = findProject(w, req.Project); !ok {
+			return
+		}
+		if task, ok = findTaskOrNew(w, project, req.Task, req.New); !ok {
+			return
+		}
+		state = model.GetState()
+		if state.Running {
+			if task != nil && state.Task.Id == task.Id {
+				writeJSON(w, http.StatusOK, newStateJSON(state))
+				return project, task, state, false
+			}
+			if !stopRunning {
+				writeError(w, http.StatusConflict, fmt.Sprintf("already running %s %s", state.Task.Project.Name, state.Task.Name))
+				return project, task, state, false
+			}
+		}
+		return
+	}
+
+	project, task, _, ok := check()
+	if !ok {
+		return
+	}
+
+	// The task may not exist yet, so give the hook what it would be called
+	hookTask := task
+	if hookTask == nil {
+		hookTask = &model.Task{Project: project, Name: req.Task}
+	}
+	now := time.Now()
+	var err error
+	s.unlocked(func() {
+		err = hooks.Run(hooks.PreStart, hooks.ActionStart, &model.Frame{Task: hookTask, StartTime: now})
+	})
+	if err != nil {
+		writeError(w, http.StatusConflict, fmt.Sprintf("aborted by hook %s", err))
+		return
+	}
+
+	// Other requests may have changed the task or state while the hook ran
+	project, task, state, ok := check()
+	if !ok {
+		return
+	}
+
+	if state.Running {
+		frame := model.GetRunningFrame()
+		// A frame started while the hook ran can't be stopped before it
+		// started
+		if frame.StartTime.After(now) {
+			now = time.Now()
+		}
+		frame.EndTime = now
+		stopFrame(frame)
+	}
+
+	if task == nil {
+		task = project.AddTask(req.Task)
+	}
+	frame := model.StartFrame(task, now)
+	runPostHook(hooks.PostStart, hooks.ActionStart, frame)
+	webhook.Notify(webhook.EventStart, frame)
+
+	writeJSON(w, http.StatusOK, newStateJSON(model.GetState()))
+}
+
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	frame := model.GetRunningFrame()
+	if frame == nil {
+		writeError(w, http.StatusConflict, "not running")
+		return
+	}
+
+	frame.EndTime = time.Now()
+	stopFrame(frame)
+	writeJSON(w, http.StatusOK, newFrameJSON(frame))
+}
+
+// Resolve a project like the CLI does, writing an error if no project or more
+// than one project matches. Archived projects can't be started.
+func findProject(w http.ResponseWriter, name string) (*model.Project, bool) {
+	if p := model.GetProjectByName(name); p != nil && p.Archived {
+		writeError(w, http.StatusConflict, fmt.Sprintf("project %s is archived", p.Name))
+		return nil, false
+	}
+	projects := model.ActiveProjects(model.FindProjects(name))
+	switch len(projects) {
+	case 1:
+		return projects[0], true
+	case 0:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("project %s doesn't exist", name))
+	default:
+		var names []string
+		for _, p := range projects {
+			names = append(names, p.Name)
+		}
+		writeError(w, http.StatusConflict, fmt.Sprintf("project %s is ambiguous: %s", name, strings.Join(names, ", ")))
+	}
+	return nil, false
+}
+
+// Resolve a task like the CLI does, returning a nil task if a new task should
+// be added. Instead of asking for confirmation, adding a task similar to an
+// existing task is a conflict unless force is set.
+func findTaskOrNew(w http.ResponseWriter, project *model.Project, name string, force bool) (*model.Task, bool) {
+	if t := project.GetTask(name); t != nil && t.Archived {
+		writeError(w, http.StatusConflict, fmt.Sprintf("task %s is archived", t.Name))
+		return nil, false
+	}
+
+	if force {
+		return project.GetTask(name), true
+	}
+
+	var tasks []*model.Task
+	var msg string
+	if tasks = model.ActiveTasks(project.FindTasksByPrefix(name)); len(tasks) == 1 {
+		return tasks[0], true
+	} else if len(tasks) > 1 {
+		msg = "task %s is ambiguous: %s"
+	} else if tasks = model.ActiveTasks(project.SimilarTasks(name)); len(tasks) != 0 {
+		msg = "task %s is similar to %s, set new to add it anyway"
+	} else {
+		return nil, true
+	}
+
+	var names []string
+	for _, t := range tasks {
+		names = append(names, t.Name)
+	}
+	writeError(w, http.StatusConflict, fmt.Sprintf(msg, name, strings.Join(names, ", ")))
+	return nil, false
+}
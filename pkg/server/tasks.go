@@ -0,0 +1,146 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/hooks"
+	"github.com/jasonwoodland/track/pkg/model"
+)
+
+type taskRequest struct {
+	ProjectId       *int64  `json:"project_id"`
+	Name            *string `json:"name"`
+	EstimateSeconds *int64  `json:"estimate_seconds"`
+	metadataRequest
+}
+
+func (s *Server) handleTasks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var projects []*model.Project
+		if v := r.URL.Query().Get("project_id"); v != "" {
+			id, _ := strconv.ParseInt(v, 10, 64)
+			project := model.GetProjectById(id)
+			if project == nil {
+				writeError(w, http.StatusNotFound, "project doesn't exist")
+				return
+			}
+			projects = append(projects, project)
+		} else {
+			projects = model.GetProjects()
+		}
+
+		// Archived projects and tasks are only included with ?all=true
+		all := r.URL.Query().Get("all") == "true"
+		if !all {
+			projects = model.ActiveProjects(projects)
+		}
+		var tasks []*model.Task
+		for _, p := range projects {
+			if all {
+				tasks = append(tasks, p.GetTasks()...)
+			} else {
+				tasks = append(tasks, model.ActiveTasks(p.GetTasks())...)
+			}
+		}
+		sort.Slice(tasks, func(i, j int) bool {
+			if tasks[i].Project.Name != tasks[j].Project.Name {
+				return tasks[i].Project.Name < tasks[j].Project.Name
+			}
+			return tasks[i].Name < tasks[j].Name
+		})
+
+		res := []*taskJSON{}
+		for _, t := range tasks {
+			res = append(res, newTaskJSON(t))
+		}
+		writeJSON(w, http.StatusOK, res)
+
+	case http.MethodPost:
+		req := taskRequest{}
+		if !readJSON(w, r, &req) || !req.checkColor(w) {
+			return
+		}
+		if req.ProjectId == nil || req.Name == nil || *req.Name == "" {
+			writeError(w, http.StatusBadRequest, "project_id and name are required")
+			return
+		}
+		project := model.GetProjectById(*req.ProjectId)
+		if project == nil {
+			writeError(w, http.StatusNotFound, "project doesn't exist")
+			return
+		}
+		if project.GetTask(*req.Name) != nil {
+			writeError(w, http.StatusConflict, "task "+*req.Name+" already exists on "+project.Name)
+			return
+		}
+
+		task := project.AddTask(*req.Name)
+		if req.EstimateSeconds != nil {
+			task.SetEstimate(time.Duration(*req.EstimateSeconds) * time.Second)
+		}
+		req.set(task)
+		writeJSON(w, http.StatusCreated, newTaskJSON(task))
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := idFromPath(w, r, "/tasks/")
+	if !ok {
+		return
+	}
+
+	task := model.GetTaskById(id)
+	if task.Project == nil {
+		writeError(w, http.StatusNotFound, "task doesn't exist")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, newTaskJSON(&task))
+
+	case http.MethodPatch:
+		req := taskRequest{}
+		if !readJSON(w, r, &req) || !req.checkColor(w) {
+			return
+		}
+		if req.Name != nil && task.RenamedPath(*req.Name) != task.Name {
+			if *req.Name == "" {
+				writeError(w, http.StatusBadRequest, "name can't be empty")
+				return
+			}
+			if path := task.RenamedPath(*req.Name); task.Project.GetTask(path) != nil {
+				writeError(w, http.StatusConflict, "task "+path+" already exists on "+task.Project.Name)
+				return
+			}
+			if err := task.Rename(*req.Name); err != nil {
+				writeError(w, http.StatusConflict, err.Error())
+				return
+			}
+		}
+		if req.EstimateSeconds != nil {
+			task.SetEstimate(time.Duration(*req.EstimateSeconds) * time.Second)
+		}
+		req.set(&task)
+		writeJSON(w, http.StatusOK, newTaskJSON(&task))
+
+	case http.MethodDelete:
+		frames := task.GetFrames()
+		for _, subtask := range task.GetSubtasks() {
+			frames = append(frames, subtask.GetFrames()...)
+		}
+		task.Remove()
+		runPostEditHooks(hooks.ActionRemove, frames)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPatch, http.MethodDelete)
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/model"
+	"github.com/jasonwoodland/track/pkg/webhook"
+)
+
+// How often to retry webhook events which failed, and to pick up events queued
+// by the CLI
+const webhookInterval = 30 * time.Second
+
+// DeliverWebhooks delivers queued webhook events after each request which
+// changes data, and periodically to retry failed events. The database is
+// only locked while claiming and recording events, so slow receivers don't
+// block requests.
+func (s *Server) DeliverWebhooks() {
+	ticker := time.NewTicker(webhookInterval)
+	defer ticker.Stop()
+	for {
+		s.deliverWebhooks()
+
+		select {
+		case <-ticker.C:
+		case <-s.changed:
+		}
+	}
+}
+
+// Deliver the queued webhook events. Events which can't be recorded because
+// of a database error are claimed again once their retry is due.
+func (s *Server) deliverWebhooks() {
+	defer func() {
+		recoverDbError(recover())
+	}()
+
+	var entries []*model.OutboxEntry
+	s.locked(func() {
+		entries = webhook.Claim()
+	})
+
+	for _, e := range entries {
+		err := webhook.Send(e)
+		s.locked(func() {
+			webhook.Record(e, err)
+		})
+	}
+}
+
+// Run fn while holding the write lock
+func (s *Server) locked(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn()
+}
+
+// Run fn without the write lock in a request which holds it, so slow work
+// like pre-* hooks doesn't block other requests
+func (s *Server) unlocked(fn func()) {
+	s.mu.Unlock()
+	defer s.mu.Lock()
+	fn()
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+	sparks     = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+)
+
+// Bar returns a horizontal bar for d relative to max, using eighth blocks for
+// the remainder, padded with spaces to width columns
+func Bar(d, max time.Duration, width int) string {
+	if max <= 0 || d <= 0 || width <= 0 {
+		return strings.Repeat(" ", width)
+	}
+	eighths := int(float64(d) / float64(max) * float64(width*8))
+	if eighths == 0 {
+		eighths = 1
+	}
+	bar := strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+	return bar + strings.Repeat(" ", width-utf8.RuneCountInString(bar))
+}
+
+// Sparkline returns a line of one block per duration, scaled to the largest
+func Sparkline(durations []time.Duration) string {
+	var max time.Duration
+	for _, d := range durations {
+		if d > max {
+			max = d
+		}
+	}
+
+	var sb strings.Builder
+	for _, d := range durations {
+		if max == 0 || d == 0 {
+			sb.WriteString(" ")
+			continue
+		}
+		sb.WriteString(sparks[int(float64(d)/float64(max)*float64(len(sparks)-1)+0.5)])
+	}
+	return sb.String()
+}
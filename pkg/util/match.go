@@ -0,0 +1,112 @@
+package util
+
+import "strings"
+
+// Match returns the indexes of the names which best match the query. An
+// exact match is preferred, then a case-insensitive match, then names
+// starting with the query and finally names containing the characters of the
+// query in order (eg. "rvw" matches "review"). More than one index means the
+// query is ambiguous.
+func Match(query string, names []string) []int {
+	return match(query, names, true)
+}
+
+// MatchPrefix is like Match, but without matching the characters of the query
+// in order, for commands which add a new name when nothing matches. A new
+// name like "api" shouldn't match "apple-pie".
+func MatchPrefix(query string, names []string) []int {
+	return match(query, names, false)
+}
+
+func match(query string, names []string, subsequence bool) []int {
+	lower := strings.ToLower(query)
+	matchers := []func(name string) bool{
+		func(name string) bool { return name == query },
+		func(name string) bool { return strings.ToLower(name) == lower },
+		func(name string) bool { return strings.HasPrefix(strings.ToLower(name), lower) },
+	}
+	if subsequence {
+		matchers = append(matchers, func(name string) bool { return isSubsequence(lower, strings.ToLower(name)) })
+	}
+	for _, m := range matchers {
+		var matches []int
+		for i, name := range names {
+			if m(name) {
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) != 0 {
+			return matches
+		}
+	}
+	return nil
+}
+
+// Similar returns the indexes of the names which are a small number of typos
+// away from the query (eg. "reveiw" is similar to "review")
+func Similar(query string, names []string) []int {
+	maxDistance := 1
+	if len([]rune(query)) > 4 {
+		maxDistance = 2
+	}
+	var similar []int
+	for i, name := range names {
+		if editDistance(strings.ToLower(query), strings.ToLower(name)) <= maxDistance {
+			similar = append(similar, i)
+		}
+	}
+	return similar
+}
+
+func isSubsequence(s, of string) bool {
+	r := []rune(s)
+	if len(r) == 0 {
+		return false
+	}
+	for _, c := range of {
+		if c == r[0] {
+			r = r[1:]
+			if len(r) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Number of insertions, deletions, substitutions and transpositions of
+// adjacent characters needed to turn a into b
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = minOf(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = minOf(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func minOf(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	names := []string{"review", "Design", "design-system", "apple-pie"}
+
+	tests := []struct {
+		query  string
+		match  []int
+		prefix []int
+	}{
+		{"review", []int{0}, []int{0}},
+		{"design", []int{1}, []int{1}},
+		{"des", []int{1, 2}, []int{1, 2}},
+		{"rvw", []int{0}, nil},
+		{"api", []int{3}, nil},
+		{"build", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := Match(tt.query, names); !reflect.DeepEqual(got, tt.match) {
+				t.Errorf("Match = %v, want %v", got, tt.match)
+			}
+			if got := MatchPrefix(tt.query, names); !reflect.DeepEqual(got, tt.prefix) {
+				t.Errorf("MatchPrefix = %v, want %v", got, tt.prefix)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+//go:build !windows
+// +build !windows
+
+package util
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// TerminalSize returns the number of columns and rows of the terminal
+// attached to stdout, falling back to $COLUMNS and $LINES or 80x24
+func TerminalSize() (width, height int) {
+	var ws struct {
+		Row, Col, Xpixel, Ypixel uint16
+	}
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		os.Stdout.Fd(),
+		uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(&ws)),
+	)
+	if errno == 0 && ws.Col != 0 && ws.Row != 0 {
+		return int(ws.Col), int(ws.Row)
+	}
+	return sizeFromEnv()
+}
+
+// TerminalWidth returns the number of columns of the terminal
+func TerminalWidth() int {
+	width, _ := TerminalSize()
+	return width
+}
+
+// MakeRaw switches the terminal to read keys as they're pressed without
+// echoing them, returning a function to restore the previous mode. Signals
+// such as SIGINT are still sent.
+func MakeRaw() (restore func(), err error) {
+	stty := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		return cmd.Output()
+	}
+
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	return func() {
+		stty(strings.TrimSpace(string(state)))
+	}, nil
+}
+
+func sizeFromEnv() (width, height int) {
+	width, height = 80, 24
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		width = cols
+	}
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		height = lines
+	}
+	return
+}
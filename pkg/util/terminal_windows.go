@@ -0,0 +1,29 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"strconv"
+)
+
+// TerminalSize returns $COLUMNS and $LINES or 80x24
+func TerminalSize() (width, height int) {
+	width, height = 80, 24
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		width = cols
+	}
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		height = lines
+	}
+	return
+}
+
+// TerminalWidth returns the number of columns of the terminal
+func TerminalWidth() int {
+	width, _ := TerminalSize()
+	return width
+}
+
+func MakeRaw() (restore func(), err error) {
+	return nil, errors.New("not supported on windows")
+}
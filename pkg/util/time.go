@@ -88,6 +88,51 @@ func TimeFromShorthand(v string) (t time.Time) {
 	return time.Time{}
 }
 
+// DateRangeFromShorthand parses a single date or a range of dates separated
+// by "..", eg. 2026-12-24..2026-12-31
+func DateRangeFromShorthand(v string) (from time.Time, to time.Time) {
+	if i := strings.Index(v, ".."); i != -1 {
+		from = TimeFromShorthand(v[:i])
+		to = TimeFromShorthand(v[i+2:])
+	} else {
+		from = TimeFromShorthand(v)
+		to = from
+	}
+	if to.Before(from) {
+		log.Fatalf("bad date range provided: %s", v)
+	}
+	return
+}
+
+// StartOfWeek returns midnight on the first day of the week containing t
+func StartOfWeek(t time.Time, firstDay time.Weekday) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return t.AddDate(0, 0, -((int(t.Weekday())-int(firstDay))+7)%7)
+}
+
+// WeekFromShorthand returns the start of an ISO week (eg. 2026-w42 or w42 for
+// the current year), adjusted to begin on firstDay
+func WeekFromShorthand(v string, firstDay time.Weekday) time.Time {
+	v = strings.ToLower(v)
+	year := time.Now().Year()
+	if i := strings.Index(v, "-w"); i != -1 {
+		var err error
+		if year, err = strconv.Atoi(v[:i]); err != nil {
+			log.Fatalf("bad format provided: %s", v)
+		}
+		v = v[i+1:]
+	}
+	week, err := strconv.Atoi(strings.TrimPrefix(v, "w"))
+	if err != nil || week < 1 || week > 53 {
+		log.Fatalf("bad format provided: %s", v)
+	}
+
+	// January 4th is always in ISO week 1
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.Local)
+	monday := StartOfWeek(jan4, time.Monday).AddDate(0, 0, (week-1)*7)
+	return StartOfWeek(monday, firstDay)
+}
+
 func GetHours(d time.Duration) string {
 	hours := d.Hours()
 	// s := ""
@@ -96,3 +141,36 @@ func GetHours(d time.Duration) string {
 	// }
 	return fmt.Sprintf("%.2fh", hours)
 }
+
+// GetPercent returns d as a percentage of of.
+func GetPercent(d, of time.Duration) float64 {
+	if of == 0 {
+		return 0
+	}
+	return float64(d) / float64(of) * 100
+}
+
+// GetSignedHours is like GetHours but always includes the sign, eg. "+1.50h"
+func GetSignedHours(d time.Duration) string {
+	return fmt.Sprintf("%+.2fh", d.Hours())
+}
+
+type DayDuration struct {
+	Date     time.Time
+	Duration time.Duration
+}
+
+// SplitByDay splits the time between start and end at each midnight in the
+// location of start, returning the duration which falls on each day
+func SplitByDay(start, end time.Time) (days []DayDuration) {
+	for start.Before(end) {
+		date := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+		next := date.AddDate(0, 0, 1)
+		if next.After(end) {
+			next = end
+		}
+		days = append(days, DayDuration{Date: date, Duration: next.Sub(start)})
+		start = next
+	}
+	return
+}
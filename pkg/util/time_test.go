@@ -0,0 +1,81 @@
+package util
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestSplitByDay(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(v string) time.Time {
+		t, err := time.ParseInLocation("2006-01-02 15:04", v, berlin)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	date := func(v string) time.Time {
+		return at(v + " 00:00")
+	}
+
+	tests := []struct {
+		name  string
+		start string
+		end   string
+		want  []DayDuration
+	}{
+		{
+			"same day",
+			"2026-10-14 09:00", "2026-10-14 17:00",
+			[]DayDuration{{date("2026-10-14"), 8 * time.Hour}},
+		},
+		{
+			"across midnight",
+			"2026-10-14 23:00", "2026-10-15 01:00",
+			[]DayDuration{{date("2026-10-14"), time.Hour}, {date("2026-10-15"), time.Hour}},
+		},
+		{
+			"ends at midnight",
+			"2026-10-14 22:00", "2026-10-15 00:00",
+			[]DayDuration{{date("2026-10-14"), 2 * time.Hour}},
+		},
+		{
+			"across dst change",
+			"2026-10-24 22:00", "2026-10-25 04:00",
+			[]DayDuration{{date("2026-10-24"), 2 * time.Hour}, {date("2026-10-25"), 5 * time.Hour}},
+		},
+		{
+			"whole dst day",
+			"2026-10-25 00:00", "2026-10-26 00:00",
+			[]DayDuration{{date("2026-10-25"), 25 * time.Hour}},
+		},
+		{
+			"across months",
+			"2026-10-31 22:00", "2026-11-01 02:00",
+			[]DayDuration{{date("2026-10-31"), 2 * time.Hour}, {date("2026-11-01"), 2 * time.Hour}},
+		},
+		{
+			"empty",
+			"2026-10-14 09:00", "2026-10-14 09:00",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitByDay(at(tt.start), at(tt.end))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d days, want %d: %v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !got[i].Date.Equal(tt.want[i].Date) || got[i].Duration != tt.want[i].Duration {
+					t.Errorf("day %d = %v %v, want %v %v", i, got[i].Date, got[i].Duration, tt.want[i].Date, tt.want[i].Duration)
+				}
+			}
+		})
+	}
+}
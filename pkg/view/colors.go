@@ -0,0 +1,72 @@
+package view
+
+// Colors for telling projects apart where they're shown side by side, eg. in
+// the hourly timeline
+var ProjectColors = []string{
+	"magenta",
+	"blue",
+	"cyan",
+	"green",
+	"yellow",
+	"red",
+	"lightMagenta",
+	"lightBlue",
+	"lightCyan",
+	"lightGreen",
+	"lightYellow",
+	"lightRed",
+}
+
+func ProjectColor(projectId int64) string {
+	if projectId < 1 {
+		return ProjectColors[0]
+	}
+	return ProjectColors[(projectId-1)%int64(len(ProjectColors))]
+}
+
+// The colors project and task names are shown in, unless they've been set
+// with project set --color or task set --color
+const (
+	DefaultProjectColor = "magenta"
+	DefaultTaskColor    = "blue"
+)
+
+// IsColor is true if the color can be used for a project or task
+func IsColor(color string) bool {
+	for _, c := range ProjectColors {
+		if c == color {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectName wraps the name of a project in its color tag, or the default
+// color if it doesn't have one
+func ProjectName(name, color string) string {
+	return "<" + ProjectNameColor(color) + ">" + name + "</>"
+}
+
+// ProjectNameColor returns the color tag for a project, or the default color
+// if it doesn't have one
+func ProjectNameColor(color string) string {
+	if color == "" {
+		return DefaultProjectColor
+	}
+	return color
+}
+
+// TaskName wraps the name of a task in its color tag, or the default color if
+// it doesn't have one
+func TaskName(name, color string) string {
+	return "<" + TaskColor(color) + ">" + name + "</>"
+}
+
+// TaskColor returns the color tag for a task, or the default color if it
+// doesn't have one
+func TaskColor(color string) string {
+	if color == "" {
+		return DefaultTaskColor
+	}
+	return color
+}
@@ -1,45 +1,143 @@
 package view
 
 const (
-	AddedProject                           = "Added project <magenta>%s</>\n"
-	AddedProjectTaskDurationTotal          = "Added: <magenta>%s</> <blue>%s</> (%s, %s total)\n"
-	AddedTask                              = "Added task <blue>%s</>\n"
-	AlreadyRunningProjectTaskElapsedTotal  = "Already running: <magenta>%s</> <blue>%s</> (%s, %s total)\033[J\n"
-	CancelledProjectTaskDurationTotal      = "Cancelled: <magenta>%s</> <blue>%s</> (%s, %s total)\n"
-	ConfirmDeleteFrameTimeProjectTask      = "Delete frame <green>%s - %s</> on <magenta>%s</> <blue>%s</>?"
-	ConfirmDeleteProject                   = "Delete project <magenta>%s</>?"
-	ConfirmDeleteTaskFramesOnProject       = "Delete task <blue>%s</> and %d frame%s on project <magenta>%s</>?"
-	ConfirmStopRunningTask                 = "Stop running task?"
-	Deleted                                = "Delete"
-	DeletedProject                         = "Deleted project <magenta>%s</>\n"
-	FinishedAtTimeElapsed                  = "Finished at <green>%s</> (%s)\n"
-	FrameDoesNotExistForProjectTask        = "Frame <gray>[%v]</> doesn't exist on <magenta>%s</> <blue>%s</>\n"
-	FrameTimesDuration                     = "  <gray>[%v]</> <green>%s - %s</> %6s\n"
-	FrameTimesDurationLog                  = "  <gray>[%v]</> <green>%s - %s</> %6s\n"
-	FrameTimesDurationTask                 = "  <green>%s - %s</> %6s <blue>%-*s</>\n"
-	DailyDateHours                         = "<green>%s</> %6s\n"
-	DailyHoursProject                      = "  %5s <magenta>%s</>\n"
-	DailyHoursTask                         = "  %5s   <blue>%-*s</>\n"
-	ConfirmMoveFrameTimesFromToProjectTask = "Move frame <green>%s - %s</> from <magenta>%s</> <blue>%s</> to <magenta>%s</> <blue>%s</>?"
-	Moved                                  = "Moved"
-	NotRunning                             = "Not running"
-	Project                                = "<magenta>%s</>\n"
-	ProjectAlreadyExists                   = "Project <magenta>%s</> already exists\n"
-	ProjectDoesNotExist                    = "Project <magenta>%s</> doesn't exist\n"
-	ProjectHours                           = "<magenta>%s</> %.2fh\n"
-	TotalHours                             = "Total: %.2fh\n"
-	RenamedProject                         = "Renamed project <magenta>%s</> to <magenta>%s</>\n"
-	RenamedTaskOnProject                   = "Renamed task <blue>%s</> to <blue>%s</> on project <magenta>%s</>\n"
-	RunningProjectTaskElapsedTotal         = "Running: <magenta>%s</> <blue>%s</> (%s, %s total)\033[J\n"
-	RunningProjectTaskPrevElapsedTotal     = "Running: <magenta>%s</> <blue>%s</> (%s -> %s, %s -> %s total)\033[J\n"
-	RunningProjectTaskTotal                = "Running: <magenta>%s</> <blue>%s</> (%s)\033[J\n"
-	StartedAtTime                          = "Started at <green>%s</>\n"
-	StartedAtTimeElapsed                   = "Started at <green>%s</> (%s ago)\033[J\n"
-	StartedAtPrevTimeElapsed               = "Started at <green>%s -> %s</> (%s -> %s ago)\033[J\n"
-	StoppedProjectTaskElapsedTotal         = "Stopped: <magenta>%s</> <blue>%s</> (%s, %s total)\033[J\n"
-	Task                                   = "  <blue>%s</>\n"
-	TaskAlreadyExistsForProject            = "Task <blue>%s</> already exists on <magenta>%s</>\n"
-	TaskDoesNotExistForProject             = "Task <blue>%s</> doesn't exist on <magenta>%s</>\n"
-	ConfirmMergeFramesFromToProjectTask    = "Merge %d frame%s from <magenta>%s</> <blue>%s</> into <magenta>%s</> <blue>%s</>?"
-	Merged                                 = "Merged"
+	AddedProject                             = "Added project <magenta>%s</>\n"
+	AddedProjectTaskDurationTotal            = "Added: %s %s (%s, %s total)\n"
+	AddedTask                                = "Added task %s\n"
+	AlreadyRunningProjectTaskElapsedTotal    = "Already running: %s %s (%s, %s total)\033[J\n"
+	CancelledProjectTaskDurationTotal        = "Cancelled: %s %s (%s, %s total)\n"
+	ConfirmDeleteFrameTimeProjectTask        = "Delete frame <green>%s - %s</> on %s %s?"
+	ConfirmDeleteProject                     = "Delete project %s?"
+	ConfirmDeleteTaskFramesOnProject         = "Delete task %s and %d frame%s on project %s?"
+	ConfirmStopRunningTask                   = "Stop running task?"
+	Deleted                                  = "Delete"
+	DeletedProject                           = "Deleted project %s\n"
+	FinishedAtTimeElapsed                    = "Finished at <green>%s</> (%s)\n"
+	FrameDoesNotExistForProjectTask          = "Frame <gray>[%v]</> doesn't exist on %s %s\n"
+	FrameTimesDuration                       = "  <gray>[%v]</> <green>%s - %s</> %6s\n"
+	FrameTimesDurationLog                    = "  <gray>[%v]</> <green>%s - %s</> %6s\n"
+	FrameNote                                = "      <gray>%s</>\n"
+	FrameTimesDurationTask                   = "  <green>%s - %s</> %6s <%s>%-*s</>\n"
+	DailyDateHours                           = "<green>%s</> %6s\n"
+	DailyHoursProject                        = "  %5s %s\n"
+	DailyHoursTask                           = "  %5s   <%s>%-*s</>\n"
+	ConfirmMoveFrameTimesFromToProjectTask   = "Move frame <green>%s - %s</> from %s %s to %s %s?"
+	Moved                                    = "Moved"
+	NotRunning                               = "Not running"
+	Project                                  = "%s\n"
+	ProjectAlreadyExists                     = "Project <magenta>%s</> already exists\n"
+	ProjectDoesNotExist                      = "Project <magenta>%s</> doesn't exist\n"
+	ProjectHours                             = "%s %.2fh\n"
+	TotalHours                               = "Total: %.2fh\n"
+	RenamedProject                           = "Renamed project %s to %s\n"
+	RenamedTaskOnProject                     = "Renamed task %s to %s on project %s\n"
+	RunningProjectTaskElapsedTotal           = "Running: %s %s (%s, %s total)\033[J\n"
+	RunningProjectTaskPrevElapsedTotal       = "Running: %s %s (%s -> %s, %s -> %s total)\033[J\n"
+	RunningProjectTaskTotal                  = "Running: %s %s (%s)\033[J\n"
+	StartedAtTime                            = "Started at <green>%s</>\n"
+	StartedAtTimeElapsed                     = "Started at <green>%s</> (%s ago)\033[J\n"
+	StartedAtPrevTimeElapsed                 = "Started at <green>%s -> %s</> (%s -> %s ago)\033[J\n"
+	StoppedProjectTaskElapsedTotal           = "Stopped: %s %s (%s, %s total)\033[J\n"
+	Task                                     = "  %s\n"
+	TaskAlreadyExistsForProject              = "Task %s already exists on %s\n"
+	TaskDoesNotExistForProject               = "Task %s doesn't exist on %s\n"
+	ConfirmMergeFramesFromToProjectTask      = "Merge %d frame%s from %s %s into %s %s?"
+	Merged                                   = "Merged"
+	EstimateUsage                            = "<gray>%s of %s (%.0f%%)</>"
+	EstimateUsageWarn                        = "<yellow>%s of %s (%.0f%%)</>"
+	EstimateUsageOver                        = "<red>%s of %s (%.0f%%)</>"
+	FrameTimesDurationTaskUsage              = "  <green>%s - %s</> %6s <%s>%-*s</> %s\n"
+	ProjectHoursUsage                        = "%s %.2fh %s\n"
+	ProjectUsage                             = "%s %s\n"
+	EstimateSetForTask                       = "Estimate for %s set to %s\n"
+	EstimateRemovedForTask                   = "Estimate for %s removed\n"
+	BudgetSetForProject                      = "Budget for %s set to %s\n"
+	BudgetRemovedForProject                  = "Budget for %s removed\n"
+	WarnTaskEstimateUsed                     = "<yellow>Warning:</> %s has used %.0f%% of its %s estimate\n"
+	WarnTaskOverEstimate                     = "<red>Over estimate:</> %s has used %.0f%% of its %s estimate\n"
+	WarnProjectBudgetUsed                    = "<yellow>Warning:</> %s has used %.0f%% of its %s budget\n"
+	WarnProjectOverBudget                    = "<red>Over budget:</> %s has used %.0f%% of its %s budget\n"
+	ConfigKeyValue                           = "<cyan>%s</> %s\n"
+	ConfigKeyDoesNotExist                    = "Setting <cyan>%s</> doesn't exist\n"
+	ConfigKeyUnset                           = "Reset <cyan>%s</> to its default\n"
+	BalanceStartRequired                     = "No start date, use --from or set <cyan>balance-start</> with the config command\n"
+	BalanceWeek                              = "<green>%s - %s</> %8s %8s %s %s\n"
+	BalanceWeekHeader                        = "<gray>%-23s %8s %8s %8s %8s</>\n"
+	BalanceDay                               = "  <gray>%-21s</> %8s %8s %s <yellow>%s</>\n"
+	BalanceTotal                             = "Balance: %s\n"
+	AddedAbsence                             = "Added <yellow>%s</> <green>%s - %s</> (%d day%s)\n"
+	AbsenceDatesTypeDays                     = "<gray>[%v]</> <green>%s - %s</> <yellow>%-8s</> %3d day%s %s\n"
+	AbsenceDoesNotExist                      = "Absence <gray>[%v]</> doesn't exist\n"
+	BadAbsenceType                           = "Bad absence type <yellow>%s</> (expected one of %s)\n"
+	ConfirmDeleteAbsence                     = "Delete <yellow>%s</> <green>%s - %s</>?"
+	ImportedHolidaysSkipped                  = "Imported %d holiday%s (%d skipped)\n"
+	DailyDateHoursAbsence                    = "<green>%s</> %6s <yellow>%s</>\n"
+	DailySparkline                           = "<gray>%s</> <green>%s</> <gray>%s</>\n"
+	HeatmapTotalDays                         = "Total: %.2fh over %d days in %d\n"
+	ProjectAmbiguous                         = "Project <magenta>%s</> is ambiguous, did you mean %s?\n"
+	TaskAmbiguousForProject                  = "Task %s is ambiguous on %s, did you mean %s?\n"
+	DidYouMean                               = "Did you mean %s?\n"
+	ConfirmAddSimilarTaskOnProject           = "Task %s is similar to %s on %s, add it anyway?"
+	ServerListening                          = "Listening on <cyan>http://%s</>\n"
+	HookAborted                              = "<red>Aborted by hook</> %s\n"
+	HookFailed                               = "<yellow>Hook failed</> %s\n"
+	AddedWebhook                             = "Added webhook <gray>[%v]</> <cyan>%s</> (%s)\n"
+	WebhookUrlEvents                         = "<gray>[%v]</> <cyan>%s</> %s\n"
+	WebhookPendingError                      = "    %d pending event%s <red>%s</>\n"
+	WebhookDoesNotExist                      = "Webhook <gray>[%v]</> doesn't exist\n"
+	BadWebhookEvent                          = "Bad event <yellow>%s</> (expected one of %s)\n"
+	BadWebhookUrl                            = "Bad URL <cyan>%s</> (expected an http or https URL)\n"
+	ConfirmDeleteWebhook                     = "Delete webhook <cyan>%s</> and %d pending event%s?"
+	SentTestEvent                            = "Sent a test event to <cyan>%s</>\n"
+	SendFailed                               = "<red>Failed:</> %s\n"
+	GitError                                 = "<red>Git:</> %s\n"
+	InstalledGitHook                         = "Installed <cyan>%s</>\n"
+	GitHookAlreadyInstalled                  = "Already installed <cyan>%s</>\n"
+	GitHookExists                            = "<cyan>%s</> already exists, add this line to it to switch tasks when checking out a branch:\n\n    %s\n"
+	DirSetForProject                         = "Directory for %s set to <cyan>%s</>\n"
+	DirRemovedForProject                     = "Directory for %s removed\n"
+	NoProjectForDir                          = "No project for this directory, add a <cyan>.track</> file or set one with <cyan>track project set --dir</>\n"
+	WarnProjectNotForDir                     = "<yellow>Warning:</> running %s, but this directory is for %s\n"
+	FrameCommit                              = "      <yellow>%s</> %s\n"
+	SavedFrameNotes                          = "Saved notes for %d frame%s\n"
+	BadFormat                                = "Bad format <yellow>%s</> (expected %s)\n"
+	ImportedFramesUpdatedSkipped             = "Imported %d frame%s (%d updated, %d skipped)\n"
+	ConfirmDeleteTaskSubtasksFramesOnProject = "Delete task %s, %d subtask%s and %d frame%s on project %s?"
+	CantRenameTask                           = "<red>Can't rename:</> %s\n"
+	CantMergeTask                            = "<red>Can't merge:</> %s\n"
+	AddedClient                              = "Added client <cyan>%s</>\n"
+	ClientAlreadyExists                      = "Client <cyan>%s</> already exists\n"
+	ClientDoesNotExist                       = "Client <cyan>%s</> doesn't exist\n"
+	ClientAmbiguous                          = "Client <cyan>%s</> is ambiguous, did you mean %s?\n"
+	RenamedClient                            = "Renamed client <cyan>%s</> to <cyan>%s</>\n"
+	ConfirmDeleteClient                      = "Delete client <cyan>%s</>? Its %d project%s will be kept"
+	DeletedClient                            = "Deleted client <cyan>%s</>\n"
+	ClientProjects                           = "<cyan>%s</> %s\n"
+	ClientSetForProject                      = "Client for %s set to <cyan>%s</>\n"
+	ClientRemovedForProject                  = "Client for %s removed\n"
+	ClientHours                              = "<cyan>%s</> %.2fh\n"
+	ClientHoursUsage                         = "<cyan>%s</> %.2fh %s\n"
+	DailyHoursClient                         = "  %5s <cyan>%s</>\n"
+	ArchivedProject                          = "Archived project %s\n"
+	UnarchivedProject                        = "Unarchived project %s\n"
+	ArchivedTaskOnProject                    = "Archived task %s on %s\n"
+	UnarchivedTaskOnProject                  = "Unarchived task %s on %s\n"
+	ProjectIsArchived                        = "Project %s is archived, unarchive it with <cyan>track project unarchive</>\n"
+	TaskIsArchivedForProject                 = "Task %s is archived on %s, unarchive it with <cyan>track task unarchive</>\n"
+	ArchivedProjectName                      = "%s <gray>(archived)</>\n"
+	BadColor                                 = "Bad color <yellow>%s</> (expected one of %s)\n"
+	DescriptionSetFor                        = "Description for %s set to <gray>%s</>\n"
+	DescriptionRemovedFor                    = "Description for %s removed\n"
+	ColorSetFor                              = "Color for %s set\n"
+	ColorRemovedFor                          = "Color for %s removed\n"
+	RefSetFor                                = "Reference for %s set to <yellow>%s</>\n"
+	RefRemovedFor                            = "Reference for %s removed\n"
+	ProjectExtra                             = "%s <gray>%s</>\n"
+	TimesheetWeek                            = "<green>Week %d</> <gray>%s - %s</>\n"
+	TimesheetDay                             = "<gray>%7s</>"
+	TimesheetNoHours                         = "<gray>%7s</>"
+	TimesheetTotalHeader                     = "<gray>%8s</>\n"
+	TimesheetProjectTask                     = "<%s>%-*s</> <%s>%-*s</>"
+	FrameEndsBeforeStart                     = "<red>Can't edit:</> the frame would end before it starts\n"
+	FrameStartsInFuture                      = "<red>Can't edit:</> the frame would start in the future\n"
 )
@@ -0,0 +1,211 @@
+// Package webhook posts JSON payloads to the URLs added with the webhook
+// command when frames start and stop, or a goal is reached. Events are queued
+// in an outbox table and retried with backoff until they're delivered.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jasonwoodland/track/pkg/db"
+	"github.com/jasonwoodland/track/pkg/model"
+)
+
+// Events which webhooks can subscribe to
+const (
+	EventStart = "start"
+	EventStop  = "stop"
+	EventGoal  = "goal"
+	EventTest  = "test"
+)
+
+var Events = []string{EventStart, EventStop, EventGoal}
+
+// Goals which can be reached when a frame is stopped or added
+const (
+	GoalDay      = "day"
+	GoalEstimate = "estimate"
+	GoalBudget   = "budget"
+)
+
+const (
+	// How long a delivery can take before it's retried
+	timeout = 10 * time.Second
+
+	// Backoff after the first failed delivery, doubling after each failure
+	// up to maxBackoff
+	minBackoff = 30 * time.Second
+	maxBackoff = time.Hour
+)
+
+var client = &http.Client{Timeout: timeout}
+
+// Whether an event was queued by this process
+var queued bool
+
+type Payload struct {
+	Event     string     `json:"event"`
+	Time      time.Time  `json:"time"`
+	Project   string     `json:"project,omitempty"`
+	Task      string     `json:"task,omitempty"`
+	FrameId   int64      `json:"frame_id,omitempty"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	Seconds   int64      `json:"seconds,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	Goal      *Goal      `json:"goal,omitempty"`
+}
+
+type Goal struct {
+	Type          string `json:"type"`
+	Name          string `json:"name"`
+	TargetSeconds int64  `json:"target_seconds"`
+	TotalSeconds  int64  `json:"total_seconds"`
+}
+
+func newPayload(event string, f *model.Frame) *Payload {
+	p := &Payload{
+		Event:     event,
+		Time:      time.Now(),
+		Project:   f.Task.Project.Name,
+		Task:      f.Task.Name,
+		FrameId:   f.Id,
+		StartTime: &f.StartTime,
+		Note:      f.Note,
+	}
+	if !f.IsRunning() {
+		p.EndTime = &f.EndTime
+		p.Seconds = int64(f.EndTime.Sub(f.StartTime).Seconds())
+	}
+	return p
+}
+
+// Notify queues the event for the frame to be sent to each webhook which is
+// subscribed to it
+func Notify(event string, f *model.Frame) {
+	enqueue(newPayload(event, f))
+}
+
+// NotifyGoals queues a goal event for each goal which the stopped or added
+// frame took over its target: the work hours for the day, the task's estimate
+// or the project's budget
+func NotifyGoals(f *model.Frame) {
+	if f.IsRunning() || len(model.GetWebhooks()) == 0 {
+		return
+	}
+	duration := f.EndTime.Sub(f.StartTime)
+
+	reached := func(goalType, name string, target, total, added time.Duration) {
+		if target == 0 || total < target || total-added >= target {
+			return
+		}
+		p := newPayload(EventGoal, f)
+		p.Goal = &Goal{
+			Type:          goalType,
+			Name:          name,
+			TargetSeconds: int64(target.Seconds()),
+			TotalSeconds:  int64(total.Seconds()),
+		}
+		enqueue(p)
+	}
+
+	dayStart := time.Date(f.EndTime.Year(), f.EndTime.Month(), f.EndTime.Day(), 0, 0, 0, 0, time.Local)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	var dayTotal time.Duration
+	for _, frame := range model.GetFrames(dayStart, dayEnd) {
+		dayTotal += frame.DurationBetween(dayStart, dayEnd)
+	}
+	workHours := db.GetSettings().WorkHours[dayStart.Weekday()]
+	reached(GoalDay, dayStart.Format("2006-01-02"), workHours, dayTotal, f.DurationBetween(dayStart, dayEnd))
+
+	reached(GoalEstimate, f.Task.Name, f.Task.Estimate, f.Task.GetTotal(), duration)
+	reached(GoalBudget, f.Task.Project.Name, f.Task.Project.Budget, f.Task.Project.GetTotal(), duration)
+}
+
+func enqueue(p *Payload) {
+	var payload []byte
+	for _, w := range model.GetWebhooks() {
+		if !w.Wants(p.Event) {
+			continue
+		}
+		if payload == nil {
+			payload, _ = json.Marshal(p)
+		}
+		w.Enqueue(p.Event, payload)
+		queued = true
+	}
+}
+
+// Queued returns true if an event was queued since the process started, so
+// commands which don't change frames don't wait on deliveries
+func Queued() bool {
+	return queued
+}
+
+// Deliver sends the events in the outbox which are due. Events which fail are
+// kept and retried after a backoff.
+func Deliver() {
+	for _, e := range Claim() {
+		Record(e, Send(e))
+	}
+}
+
+// Claim returns the events in the outbox which are due, so they can be sent
+// without being sent by another process at the same time
+func Claim() []*model.OutboxEntry {
+	return model.ClaimDueOutbox(2 * timeout)
+}
+
+// Send posts a queued event to its webhook
+func Send(e *model.OutboxEntry) error {
+	return post(e.Webhook.Url, e.Event, e.Id, e.Payload)
+}
+
+// Record the result of sending a queued event, removing it from the outbox if
+// it was delivered
+func Record(e *model.OutboxEntry, err error) {
+	if err == nil {
+		e.Delivered()
+		return
+	}
+	backoff := minBackoff << e.Attempts
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	e.Failed(err, time.Now().Add(backoff))
+}
+
+// Test posts a test event straight to the webhook, bypassing the outbox
+func Test(w *model.Webhook) error {
+	payload, _ := json.Marshal(&Payload{Event: EventTest, Time: time.Now()})
+	return post(w.Url, EventTest, 0, payload)
+}
+
+func post(url, event string, id int64, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "track")
+	req.Header.Set("X-Track-Event", event)
+	if id != 0 {
+		// Receivers can use the delivery id to ignore retries of events
+		// they've already seen
+		req.Header.Set("X-Track-Delivery", strconv.FormatInt(id, 10))
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s: %s", url, res.Status)
+	}
+	return nil
+}